@@ -6,12 +6,14 @@ require (
 	github.com/ava-labs/avalanchego v1.3.0
 	github.com/ava-labs/avalanchego-kurtosis/kurtosis v0.0.0-20210427184246-8601494a1220
 	github.com/ethereum/go-ethereum v1.9.21
+	github.com/ghodss/yaml v1.0.0
 	github.com/golang/protobuf v1.4.3
 	github.com/kurtosis-tech/kurtosis-libs/golang v0.0.0-20210421174623-51de7828dfbc
 	github.com/palantir/stacktrace v0.0.0-20161112013806-78658fd2d177
 	github.com/sirupsen/logrus v1.6.0
 	github.com/status-im/keycard-go v0.0.0-20200402102358-957c09536969
 	github.com/stretchr/testify v1.7.0
+	github.com/tyler-smith/go-bip39 v1.0.2
 	google.golang.org/grpc v1.35.0
 	google.golang.org/protobuf v1.25.0
 )
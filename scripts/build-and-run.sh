@@ -26,7 +26,36 @@ fi
 
 # >>>>>>>> Add custom testsuite parameters here <<<<<<<<<<<<<
 custom_params_json='{
-    "avalancheImage": "avaplatform/avalanchego:latest"
+    "avalancheImage": "avaplatform/avalanchego:latest",
+    "numAdditionalNodes": 0,
+    "snowParams": {
+        "sampleSize": 3,
+        "quorumSize": 3,
+        "virtuousCommitThreshold": 0,
+        "rogueCommitThreshold": 0,
+        "concurrentRepolls": 0
+    },
+    "nodePorts": {
+        "httpPort": 9650,
+        "stakingPort": 9651
+    },
+    "nodeStartupPollIntervalSeconds": 5,
+    "maxNumNodeStartupPolls": 30,
+    "fundedAccountBalanceAvax": 1000,
+    "gasStrategy": {
+        "priceMode": "default",
+        "limitMode": "estimated",
+        "estimatedLimitHeadroomPercent": 20
+    },
+    "gasReportDirpath": "/suite-execution/gas-reports",
+    "gasBaselineFilepath": "smart_contracts/gas_baseline.json",
+    "contractArtifactsDirpath": "smart_contracts/artifacts",
+    "scenariosDirpath": "smart_contracts/scenarios",
+    "timeouts": {
+        "defaultSetupTimeoutSeconds": 180,
+        "defaultRunTimeoutSeconds": 180,
+        "setupTimeoutPerAdditionalNodeSeconds": 15
+    }
 }'
 # >>>>>>>> Add custom testsuite parameters here <<<<<<<<<<<<<
 
@@ -0,0 +1,10 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package bindings
+
+// Regenerates the bindings, and the artifacts in smart_contracts/artifacts, from the contracts in smart_contracts/solidity;
+//  requires solc v0.7.6 on the PATH
+//go:generate go run ../bindings_generator --solidity-dir ../solidity --bindings-dir . --artifacts-dir ../artifacts
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package bindings
+
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/smart_contracts/source_hashing"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	// Relative to this package's directory, which is where 'go test' runs tests from
+	solidityDirpath = "../solidity"
+
+	solidityFileGlob = "*.sol"
+
+	// Separates the version from the commit in solc version strings, e.g. '0.7.6+commit.7338295f'
+	solcVersionCommitSeparator = "+"
+)
+
+// Fails when any Solidity file or anything it imports has changed (or a file has been added or removed) since the
+//  bindings were generated, or when the required solc version or the generator's compiler settings have changed; needs
+//  neither solc nor a network, so it's cheap enough to run everywhere
+func TestBindingsMatchSoliditySources(t *testing.T) {
+	if !strings.HasPrefix(bindingsSolcVersion, source_hashing.RequiredSolcVersion + solcVersionCommitSeparator) {
+		t.Fatalf(
+			"The bindings were generated with solc '%v', but solc %v is required; run 'go generate ./...' with it to regenerate them",
+			bindingsSolcVersion,
+			source_hashing.RequiredSolcVersion)
+	}
+
+	solidityFilepaths, err := filepath.Glob(filepath.Join(solidityDirpath, solidityFileGlob))
+	if err != nil {
+		t.Fatalf("An error occurred finding the Solidity files in '%v': %v", solidityDirpath, err)
+	}
+	if len(solidityFilepaths) == 0 {
+		t.Fatalf("No Solidity files were found in '%v'", solidityDirpath)
+	}
+
+	solidityFilenames := map[string]bool{}
+	for _, solidityFilepath := range solidityFilepaths {
+		solidityFilename := filepath.Base(solidityFilepath)
+		solidityFilenames[solidityFilename] = true
+
+		bindingsHash, found := sourceHashes[solidityFilename]
+		if !found {
+			t.Errorf("Solidity file '%v' has no bindings; run 'go generate ./...' to generate them", solidityFilename)
+			continue
+		}
+		sourceHash, err := source_hashing.ComputeSourceHash(bindingsSolcVersion, solidityDirpath, solidityFilename)
+		if err != nil {
+			t.Fatalf("An error occurred computing the source hash of Solidity file '%v': %v", solidityFilepath, err)
+		}
+		if bindingsHash != sourceHash {
+			t.Errorf(
+				"The bindings for Solidity file '%v' were generated from a different source, different imports, or different compiler settings " +
+					"(bindings hash '%v' != current hash '%v'); run 'go generate ./...' to regenerate them",
+				solidityFilename,
+				bindingsHash,
+				sourceHash)
+		}
+	}
+
+	for solidityFilename := range sourceHashes {
+		if !solidityFilenames[solidityFilename] {
+			t.Errorf(
+				"Bindings exist for Solidity file '%v', which no longer exists; run 'go generate ./...' to remove them",
+				solidityFilename)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+// Generates Go bindings and solc combined-json artifacts for every Solidity contract in the Solidity directory, one
+//  bindings file and one artifact file per Solidity file, both from the same compilation so that they always agree
+// Usually run through 'go generate ./...', which invokes it from the bindings package directory
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/compiler"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/smart_contracts/source_hashing"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	successExitCode = 0
+	failureExitCode = 1
+
+	// Returned by --check when the bindings or artifacts are out of date, so CI can tell staleness apart from generator
+	//  failures
+	staleBindingsExitCode = 2
+
+	solidityFileExt = ".sol"
+	goFileExt       = ".go"
+	artifactFileExt = ".json"
+
+	hexStrIndicatorLeader = "0x"
+
+	// solc's combined-json output keys contracts by "source file:contract name"
+	contractKeySeparator = ":"
+
+	// Marks files written by bind.Bind, so that bindings whose Solidity file was deleted can be found
+	generatedBindingsHeader = "// Code generated - DO NOT EDIT."
+
+	generatedFilePerms = 0644
+
+	artifactJsonIndent = "    "
+
+	// Written to the bindings package alongside the bindings, so that the package's tests can tell when the bindings are
+	//  stale; starts with the same header as the bindings, so it's handled the same way as them
+	sourceHashesFilename = "source_hashes_gen.go"
+	sourceHashesFileHeader = generatedBindingsHeader + `
+// This file is generated by the bindings generator and any manual changes will be lost.
+
+package %v
+
+// Version and commit of the solc that the bindings were generated with
+const bindingsSolcVersion = %q
+
+// Hash of each Solidity file, everything it imports, the solc version, and the compiler settings that its bindings were
+// generated from, keyed by Solidity filename
+var sourceHashes = map[string]string{
+`
+	sourceHashesFileEntryTemplate = "%q: %q,\n"
+	sourceHashesFileFooter = "}\n"
+)
+
+// Picks the version and commit out of 'solc --version' output, leaving off the platform (e.g. '.Linux.g++') so that the
+//  artifacts come out the same on every OS
+var solcVersionRegex = regexp.MustCompile(`Version: (\S+\+commit\.[0-9a-f]+)`)
+
+// The subset of solc's --combined-json output that the contract registry reads
+type combinedJsonArtifact struct {
+	// Keyed by "source file:contract name"
+	Contracts map[string]combinedJsonContract	`json:"contracts"`
+
+	Version string	`json:"version"`
+}
+
+type combinedJsonContract struct {
+	// solc v0.7 serializes the ABI to a string within the JSON
+	Abi string	`json:"abi"`
+
+	// Hex, with no 0x prefix
+	Bin string	`json:"bin"`
+}
+
+func main() {
+	solcArg := flag.String(
+		"solc",
+		"solc",
+		"Path to the solc binary to compile the contracts with",
+	)
+
+	solidityDirpathArg := flag.String(
+		"solidity-dir",
+		"../solidity",
+		"Directory containing the Solidity files to generate bindings for",
+	)
+
+	bindingsDirpathArg := flag.String(
+		"bindings-dir",
+		".",
+		"Directory of the Go package to write the bindings to",
+	)
+
+	artifactsDirpathArg := flag.String(
+		"artifacts-dir",
+		"../artifacts",
+		"Directory to write the contracts' solc combined-json artifacts to, for the testsuite's contract registry",
+	)
+
+	checkArg := flag.Bool(
+		"check",
+		false,
+		fmt.Sprintf(
+			"Instead of writing the bindings and artifacts, exit with code %v if the ones on disk differ from what would be generated",
+			staleBindingsExitCode),
+	)
+
+	flag.Parse()
+
+	isUpToDate, err := run(*solcArg, *solidityDirpathArg, *bindingsDirpathArg, *artifactsDirpathArg, *checkArg)
+	if err != nil {
+		logrus.Errorf("An error occurred generating the contract bindings:")
+		fmt.Fprintln(logrus.StandardLogger().Out, err)
+		os.Exit(failureExitCode)
+	}
+	if !isUpToDate {
+		os.Exit(staleBindingsExitCode)
+	}
+	os.Exit(successExitCode)
+}
+
+// Returns false if running in check mode and the bindings or artifacts are out of date
+func run(solcPath string, solidityDirpath string, bindingsDirpath string, artifactsDirpath string, isCheckMode bool) (bool, error) {
+	solidity, err := compiler.SolidityVersion(solcPath)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred getting the version of solc binary '%v'; is Solidity installed?", solcPath)
+	}
+	if solidity.Version != source_hashing.RequiredSolcVersion {
+		return false, stacktrace.NewError(
+			"Installed version of Solidity is '%v' but must be %v",
+			solidity.Version,
+			source_hashing.RequiredSolcVersion)
+	}
+	solcVersionMatches := solcVersionRegex.FindStringSubmatch(solidity.FullVersion)
+	if solcVersionMatches == nil {
+		return false, stacktrace.NewError("Couldn't find the solc version and commit in its version output '%v'", solidity.FullVersion)
+	}
+	solcVersion := solcVersionMatches[1]
+
+	absSolidityDirpath, err := filepath.Abs(solidityDirpath)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred getting the absolute path of Solidity directory '%v'", solidityDirpath)
+	}
+	absBindingsDirpath, err := filepath.Abs(bindingsDirpath)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred getting the absolute path of bindings directory '%v'", bindingsDirpath)
+	}
+	absArtifactsDirpath, err := filepath.Abs(artifactsDirpath)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred getting the absolute path of artifacts directory '%v'", artifactsDirpath)
+	}
+	packageName := filepath.Base(absBindingsDirpath)
+
+	expectedBindings, expectedArtifacts, err := generateAll(solidity.Path, solcVersion, absSolidityDirpath, packageName)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred generating the bindings and artifacts")
+	}
+	existingBindings, err := readExistingBindings(absBindingsDirpath)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred reading the existing bindings")
+	}
+	existingArtifacts, err := readExistingArtifacts(absArtifactsDirpath, expectedArtifacts)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred reading the existing artifacts")
+	}
+
+	areBindingsUpToDate, err := syncGeneratedFiles("Bindings", absBindingsDirpath, expectedBindings, existingBindings, isCheckMode)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred updating the bindings")
+	}
+	areArtifactsUpToDate, err := syncGeneratedFiles("Artifact", absArtifactsDirpath, expectedArtifacts, existingArtifacts, isCheckMode)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred updating the artifacts")
+	}
+	if !areBindingsUpToDate || !areArtifactsUpToDate {
+		logrus.Errorf("The bindings or artifacts are out of date; regenerate them with 'go generate ./...'")
+		return false, nil
+	}
+	return true, nil
+}
+
+// Brings the generated files in the directory in line with the expected ones, or in check mode only reports whether
+//  they already are; existing files with no expected counterpart get removed, so the existing files given must only
+//  include ones that the generator owns
+// Returns false if running in check mode and the files are out of date
+func syncGeneratedFiles(
+		fileKind string,
+		dirpath string,
+		expectedFiles map[string]string,
+		existingFiles map[string]string,
+		isCheckMode bool) (bool, error) {
+	staleFilenames := []string{}
+	for filename, contents := range expectedFiles {
+		if existingContents, found := existingFiles[filename]; !found || existingContents != contents {
+			staleFilenames = append(staleFilenames, filename)
+		}
+	}
+	orphanedFilenames := []string{}
+	for filename := range existingFiles {
+		if _, found := expectedFiles[filename]; !found {
+			orphanedFilenames = append(orphanedFilenames, filename)
+		}
+	}
+	sort.Strings(staleFilenames)
+	sort.Strings(orphanedFilenames)
+
+	if isCheckMode {
+		if len(staleFilenames) == 0 && len(orphanedFilenames) == 0 {
+			logrus.Infof("All %v %v files are up to date", len(expectedFiles), strings.ToLower(fileKind))
+			return true, nil
+		}
+		for _, filename := range staleFilenames {
+			logrus.Errorf("%v file '%v' is out of date", fileKind, filename)
+		}
+		for _, filename := range orphanedFilenames {
+			logrus.Errorf("%v file '%v' has no corresponding Solidity file", fileKind, filename)
+		}
+		return false, nil
+	}
+
+	for _, filename := range staleFilenames {
+		filepathToWrite := filepath.Join(dirpath, filename)
+		if err := ioutil.WriteFile(filepathToWrite, []byte(expectedFiles[filename]), generatedFilePerms); err != nil {
+			return false, stacktrace.Propagate(err, "An error occurred writing %v file '%v'", strings.ToLower(fileKind), filepathToWrite)
+		}
+		logrus.Infof("Wrote %v file '%v'", strings.ToLower(fileKind), filepathToWrite)
+	}
+	for _, filename := range orphanedFilenames {
+		filepathToRemove := filepath.Join(dirpath, filename)
+		if err := os.Remove(filepathToRemove); err != nil {
+			return false, stacktrace.Propagate(err, "An error occurred removing orphaned %v file '%v'", strings.ToLower(fileKind), filepathToRemove)
+		}
+		logrus.Infof("Removed %v file '%v' whose Solidity file no longer exists", strings.ToLower(fileKind), filepathToRemove)
+	}
+	logrus.Infof(
+		"%v files are up to date (%v written, %v removed, %v unchanged)",
+		fileKind,
+		len(staleFilenames),
+		len(orphanedFilenames),
+		len(expectedFiles) - len(staleFilenames))
+	return true, nil
+}
+
+// Compiles each Solidity file once, and returns the bindings code and the artifact JSON generated from that compilation,
+//  each keyed by the filename it should be written to; the bindings include the file of source hashes
+func generateAll(solcPath string, solcVersion string, solidityDirpath string, packageName string) (map[string]string, map[string]string, error) {
+	// Contracts get compiled from inside the Solidity directory with relative paths, because solc embeds the source
+	//  paths in the contract metadata (and therefore the bytecode), so absolute paths would make the output differ
+	//  between machines
+	workingDirpath, err := os.Getwd()
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "An error occurred getting the working directory")
+	}
+	if err := os.Chdir(solidityDirpath); err != nil {
+		return nil, nil, stacktrace.Propagate(err, "An error occurred changing into Solidity directory '%v'", solidityDirpath)
+	}
+	defer os.Chdir(workingDirpath)
+
+	solidityFilenames, err := filepath.Glob("*" + solidityFileExt)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "An error occurred finding the Solidity files in '%v'", solidityDirpath)
+	}
+	sort.Strings(solidityFilenames)
+
+	bindings := map[string]string{}
+	artifacts := map[string]string{}
+	sourceHashes := map[string]string{}
+	for _, solidityFilename := range solidityFilenames {
+		contracts, err := compiler.CompileSolidity(solcPath, solidityFilename)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "An error occurred compiling Solidity file '%v'", solidityFilename)
+		}
+		baseFilename := strings.TrimSuffix(solidityFilename, solidityFileExt)
+
+		code, err := generateBindings(contracts, solidityFilename, packageName)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "An error occurred generating bindings for Solidity file '%v'", solidityFilename)
+		}
+		bindings[baseFilename + goFileExt] = code
+
+		artifact, err := generateArtifact(contracts, solidityFilename, solcVersion)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "An error occurred generating the artifact for Solidity file '%v'", solidityFilename)
+		}
+		artifacts[baseFilename + artifactFileExt] = artifact
+
+		sourceHash, err := source_hashing.ComputeSourceHash(solcVersion, solidityDirpath, solidityFilename)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "An error occurred computing the source hash of Solidity file '%v'", solidityFilename)
+		}
+		sourceHashes[solidityFilename] = sourceHash
+	}
+
+	sourceHashesCode, err := generateSourceHashes(packageName, solcVersion, sourceHashes)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "An error occurred generating the source hashes")
+	}
+	bindings[sourceHashesFilename] = sourceHashesCode
+	return bindings, artifacts, nil
+}
+
+// Generates bindings for the contracts declared in the given Solidity file, the same way that abigen does except that
+//  the contracts are bound in a fixed order (abigen's order is random, so its output changes from run to run) and
+//  contracts from imported files are left to their own file's bindings
+func generateBindings(contracts map[string]*compiler.Contract, solidityFilename string, packageName string) (string, error) {
+	typeNames := []string{}
+	abis := []string{}
+	bytecodes := []string{}
+	funcSigs := []map[string]string{}
+	libraries := map[string]string{}
+	for _, key := range getSortedContractKeys(contracts) {
+		contractName := getContractName(key)
+
+		// Libraries from imported files still need registering so that linking code gets generated for them
+		libraryPattern := crypto.Keccak256Hash([]byte(key)).String()[2:36]
+		libraries[libraryPattern] = contractName
+
+		if !isDeclaredIn(key, solidityFilename) {
+			continue
+		}
+		contract := contracts[key]
+		abiJson, err := json.Marshal(contract.Info.AbiDefinition)
+		if err != nil {
+			return "", stacktrace.Propagate(err, "An error occurred serializing the ABI of contract '%v'", key)
+		}
+		typeNames = append(typeNames, contractName)
+		abis = append(abis, string(abiJson))
+		bytecodes = append(bytecodes, contract.Code)
+		funcSigs = append(funcSigs, contract.Hashes)
+	}
+	if len(typeNames) == 0 {
+		return "", stacktrace.NewError("No contracts are declared in Solidity file '%v'", solidityFilename)
+	}
+
+	code, err := bind.Bind(typeNames, abis, bytecodes, funcSigs, packageName, bind.LangGo, libraries, map[string]string{})
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred generating the bindings code")
+	}
+	return code, nil
+}
+
+// Generates the Go file that records the solc version and each Solidity file's source hash
+func generateSourceHashes(packageName string, solcVersion string, sourceHashes map[string]string) (string, error) {
+	solidityFilenames := []string{}
+	for solidityFilename := range sourceHashes {
+		solidityFilenames = append(solidityFilenames, solidityFilename)
+	}
+	sort.Strings(solidityFilenames)
+
+	code := fmt.Sprintf(sourceHashesFileHeader, packageName, solcVersion)
+	for _, solidityFilename := range solidityFilenames {
+		code += fmt.Sprintf(sourceHashesFileEntryTemplate, solidityFilename, sourceHashes[solidityFilename])
+	}
+	code += sourceHashesFileFooter
+
+	formattedCode, err := format.Source([]byte(code))
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred formatting the source hashes code")
+	}
+	return string(formattedCode), nil
+}
+
+// Generates a solc combined-json artifact holding the contracts declared in the given Solidity file, keyed the way solc
+//  keyed them in the compilation
+func generateArtifact(contracts map[string]*compiler.Contract, solidityFilename string, solcVersion string) (string, error) {
+	artifact := combinedJsonArtifact{
+		Contracts: map[string]combinedJsonContract{},
+		Version:   solcVersion,
+	}
+	for _, key := range getSortedContractKeys(contracts) {
+		if !isDeclaredIn(key, solidityFilename) {
+			continue
+		}
+		contract := contracts[key]
+		abiJson, err := json.Marshal(contract.Info.AbiDefinition)
+		if err != nil {
+			return "", stacktrace.Propagate(err, "An error occurred serializing the ABI of contract '%v'", key)
+		}
+		artifact.Contracts[key] = combinedJsonContract{
+			Abi: string(abiJson),
+			Bin: strings.TrimPrefix(contract.Code, hexStrIndicatorLeader),
+		}
+	}
+	if len(artifact.Contracts) == 0 {
+		return "", stacktrace.NewError("No contracts are declared in Solidity file '%v'", solidityFilename)
+	}
+
+	// Map keys get serialized in sorted order, so the output is the same on every run
+	artifactJson, err := json.MarshalIndent(artifact, "", artifactJsonIndent)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred serializing the artifact")
+	}
+	return string(artifactJson) + "\n", nil
+}
+
+func getSortedContractKeys(contracts map[string]*compiler.Contract) []string {
+	result := []string{}
+	for key := range contracts {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func getContractName(contractKey string) string {
+	keyParts := strings.Split(contractKey, contractKeySeparator)
+	return keyParts[len(keyParts) - 1]
+}
+
+// Contracts from imported files are compiled along with the importing file's own
+func isDeclaredIn(contractKey string, solidityFilename string) bool {
+	return strings.HasPrefix(contractKey, solidityFilename + contractKeySeparator)
+}
+
+// Returns the contents of the generated bindings files in the directory, keyed by filename
+func readExistingBindings(bindingsDirpath string) (map[string]string, error) {
+	goFilepaths, err := filepath.Glob(filepath.Join(bindingsDirpath, "*" + goFileExt))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred listing the Go files in '%v'", bindingsDirpath)
+	}
+	result := map[string]string{}
+	for _, goFilepath := range goFilepaths {
+		contents, err := ioutil.ReadFile(goFilepath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred reading Go file '%v'", goFilepath)
+		}
+		if !bytes.HasPrefix(contents, []byte(generatedBindingsHeader)) {
+			continue
+		}
+		result[filepath.Base(goFilepath)] = string(contents)
+	}
+	return result, nil
+}
+
+// Returns the contents of the artifacts that the generator would write, keyed by filename; other files in the directory
+//  (e.g. artifacts from other toolchains) are left out, so that they're never flagged as orphaned or removed
+func readExistingArtifacts(artifactsDirpath string, expectedArtifacts map[string]string) (map[string]string, error) {
+	result := map[string]string{}
+	for filename := range expectedArtifacts {
+		artifactFilepath := filepath.Join(artifactsDirpath, filename)
+		contents, err := ioutil.ReadFile(artifactFilepath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred reading artifact file '%v'", artifactFilepath)
+		}
+		result[filename] = string(contents)
+	}
+	return result, nil
+}
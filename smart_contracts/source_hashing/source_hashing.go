@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+// Shared by the bindings generator, which records source hashes alongside the bindings it generates, and the bindings
+//  package's tests, which recompute them to detect stale bindings without needing solc
+package source_hashing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/palantir/stacktrace"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// This is pinned to an exact version because the compiler version changes the bytecode in the bindings; it has to be
+	//  0.7.x because AvalancheGo depends on go-ethereum v1.9.21, whose ABI parsing breaks on the output of newer Solidity
+	//  versions
+	RequiredSolcVersion = "0.7.6"
+
+	// Everything besides the Solidity sources and the solc version that affects the generated bindings: the compiler
+	//  flags (which are the ones go-ethereum's compiler package passes to solc), and the go-ethereum version whose
+	//  templates render the bindings
+	// Change this whenever any of them change, so that the bindings get flagged as stale and regenerated
+	CompilerSettings = "--combined-json bin,bin-runtime,srcmap,srcmap-runtime,abi,userdoc,devdoc,metadata,hashes " +
+		"--optimize --allow-paths ., ./, ../; " +
+		"go-ethereum v1.9.21 bind"
+
+	relativeImportPrefix = "."
+)
+
+// Matches every form of Solidity import ('import "a.sol";', 'import * as A from "a.sol";', 'import {B} from "a.sol";',
+//  etc.), capturing the imported path
+var importRegex = regexp.MustCompile(`(?m)^\s*import\s+[^"';]*["']([^"']+)["']`)
+
+// Hashes the given Solidity file, every file it imports (directly or not), the solc version (as version and commit,
+//  e.g. '0.7.6+commit.7338295f', leaving off the platform so that every OS gets the same hash), and the compiler
+//  settings
+// Imports are resolved the way solc resolves them when run from the Solidity directory: paths starting with '.' are
+//  relative to the importing file, and all others are relative to the Solidity directory
+func ComputeSourceHash(solcVersion string, solidityDirpath string, solidityFilename string) (string, error) {
+	sources, err := readSourceWithImports(solidityDirpath, solidityFilename)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred reading Solidity file '%v' and its imports", solidityFilename)
+	}
+	sourcePaths := []string{}
+	for sourcePath := range sources {
+		sourcePaths = append(sourcePaths, sourcePath)
+	}
+	sort.Strings(sourcePaths)
+
+	hasher := sha256.New()
+	hasher.Write([]byte(CompilerSettings))
+	hasher.Write([]byte{0})
+	hasher.Write([]byte(solcVersion))
+	hasher.Write([]byte{0})
+	hasher.Write([]byte(solidityFilename))
+	for _, sourcePath := range sourcePaths {
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(sourcePath))
+		hasher.Write([]byte{0})
+		hasher.Write(sources[sourcePath])
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Returns the contents of the file and everything it imports, keyed by their paths relative to the Solidity directory
+func readSourceWithImports(solidityDirpath string, solidityFilename string) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	pathsToRead := []string{path.Clean(solidityFilename)}
+	for len(pathsToRead) > 0 {
+		sourcePath := pathsToRead[0]
+		pathsToRead = pathsToRead[1:]
+		if _, found := result[sourcePath]; found {
+			continue
+		}
+		source, err := ioutil.ReadFile(filepath.Join(solidityDirpath, filepath.FromSlash(sourcePath)))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred reading Solidity file '%v'", sourcePath)
+		}
+		result[sourcePath] = source
+
+		for _, match := range importRegex.FindAllSubmatch(source, -1) {
+			importPath := string(match[1])
+			if strings.HasPrefix(importPath, relativeImportPrefix) {
+				importPath = path.Join(path.Dir(sourcePath), importPath)
+			}
+			pathsToRead = append(pathsToRead, path.Clean(importPath))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package source_hashing_test
+
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/smart_contracts/source_hashing"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testSolcVersion = "0.7.6+commit.7338295f"
+
+	mainFilename = "main.sol"
+)
+
+// main.sol imports lib/token.sol relative to itself, which imports math.sol relative to the Solidity directory;
+//  unrelated.sol isn't imported by anything
+var testSources = map[string]string{
+	mainFilename: `pragma solidity ^0.7.0;
+import {Token} from "./lib/token.sol";
+contract Main is Token {}
+`,
+	"lib/token.sol": `pragma solidity ^0.7.0;
+import "math.sol";
+contract Token {}
+`,
+	"math.sol": `pragma solidity ^0.7.0;
+library SafeMath {}
+`,
+	"unrelated.sol": `pragma solidity ^0.7.0;
+contract Unrelated {}
+`,
+}
+
+func TestComputeSourceHashCoversImportsAndSolcVersion(t *testing.T) {
+	solidityDirpath := writeTestSources(t)
+	defer os.RemoveAll(solidityDirpath)
+	originalHash := computeMainSourceHash(t, solidityDirpath, testSolcVersion)
+
+	if hash := computeMainSourceHash(t, solidityDirpath, testSolcVersion); hash != originalHash {
+		t.Fatalf("Expected the hash to be the same on every run, but got '%v' and then '%v'", originalHash, hash)
+	}
+	if hash := computeMainSourceHash(t, solidityDirpath, "0.7.5+commit.eb77ed08"); hash == originalHash {
+		t.Fatalf("Expected the hash to change with the solc version, but it didn't")
+	}
+
+	testCases := []struct {
+		filename      string
+		isHashChanged bool
+	}{
+		{filename: mainFilename, isHashChanged: true},
+		{filename: "lib/token.sol", isHashChanged: true},
+		{filename: "math.sol", isHashChanged: true},
+		{filename: "unrelated.sol", isHashChanged: false},
+	}
+	for _, testCase := range testCases {
+		writeTestSource(t, solidityDirpath, testCase.filename, testSources[testCase.filename] + "// Changed\n")
+		hash := computeMainSourceHash(t, solidityDirpath, testSolcVersion)
+		writeTestSource(t, solidityDirpath, testCase.filename, testSources[testCase.filename])
+
+		if isHashChanged := hash != originalHash; isHashChanged != testCase.isHashChanged {
+			t.Errorf(
+				"Expected changing '%v' to change the hash of '%v' to be %v, but was %v",
+				testCase.filename,
+				mainFilename,
+				testCase.isHashChanged,
+				isHashChanged)
+		}
+	}
+}
+
+func TestComputeSourceHashFailsOnMissingImport(t *testing.T) {
+	solidityDirpath := writeTestSources(t)
+	defer os.RemoveAll(solidityDirpath)
+	if err := os.Remove(filepath.Join(solidityDirpath, "math.sol")); err != nil {
+		t.Fatalf("An error occurred removing the imported file: %v", err)
+	}
+	if _, err := source_hashing.ComputeSourceHash(testSolcVersion, solidityDirpath, mainFilename); err == nil {
+		t.Fatalf("Expected an error computing the hash of a file whose import doesn't exist, but got none")
+	}
+}
+
+func writeTestSources(t *testing.T) string {
+	solidityDirpath, err := ioutil.TempDir("", "source-hashing-test")
+	if err != nil {
+		t.Fatalf("An error occurred creating the Solidity directory: %v", err)
+	}
+	for filename, source := range testSources {
+		writeTestSource(t, solidityDirpath, filename, source)
+	}
+	return solidityDirpath
+}
+
+func writeTestSource(t *testing.T, solidityDirpath string, filename string, source string) {
+	sourceFilepath := filepath.Join(solidityDirpath, filepath.FromSlash(filename))
+	if err := os.MkdirAll(filepath.Dir(sourceFilepath), 0755); err != nil {
+		t.Fatalf("An error occurred creating the directory for '%v': %v", filename, err)
+	}
+	if err := ioutil.WriteFile(sourceFilepath, []byte(source), 0644); err != nil {
+		t.Fatalf("An error occurred writing '%v': %v", filename, err)
+	}
+}
+
+func computeMainSourceHash(t *testing.T, solidityDirpath string, solcVersion string) string {
+	hash, err := source_hashing.ComputeSourceHash(solcVersion, solidityDirpath, mainFilename)
+	if err != nil {
+		t.Fatalf("An error occurred computing the source hash: %v", err)
+	}
+	return hash
+}
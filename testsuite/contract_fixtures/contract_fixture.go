@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_fixtures
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+const (
+	deployMethodPrefix = "Deploy"
+)
+
+// Binds a deployed contract to a typed handle, e.g.:
+//
+//  func(address common.Address, backend bind.ContractBackend) (interface{}, error) {
+//      return bindings.NewSimpleStorage(address, backend)
+//  }
+type BindFunc func(address common.Address, backend bind.ContractBackend) (interface{}, error)
+
+// A contract that a test needs deployed before it runs; fixtures are deployed during the test's setup, so that their
+//  deployment counts against the setup timeout rather than the run timeout
+type ContractFixture struct {
+	// What the test calls the deployed contract; must be unique among the test's fixtures
+	Name string
+
+	// Name of the contract in the contract registry
+	Contract string
+
+	// Go values of the types that the abi package expects (e.g. *big.Int for uint256)
+	ConstructorArgs []interface{}
+
+	// Name of a funded account to deploy from, which gets created along with the fixtures and can be used by the test
+	//  afterwards; if empty, the backend's transactor deploys the contract
+	Deployer string
+
+	// Optional; gives the test a typed handle (e.g. generated bindings) to the deployed contract
+	Bind BindFunc
+}
+
+// A fixture once it's been deployed and accepted by every node
+type DeployedContract struct {
+	Address common.Address
+
+	// Bound through the contract registry, for calling methods by name
+	Contract *contract_registry.DynamicContract
+
+	// Whatever the fixture's BindFunc returned, e.g. a *bindings.SimpleStorage; nil if the fixture has no BindFunc
+	Binding interface{}
+
+	DeploymentReceipt *types.Receipt
+}
+
+// Deploys the fixtures, sending every deployment before waiting for any of them so they can share blocks, and returns
+//  the backend along with the deployed contracts and their deployers
+func DeployContractFixtures(
+		ctx context.Context,
+		backend networks_impl.SmartContractBackend,
+		contractRegistry *contract_registry.ContractRegistry,
+		fixtures []ContractFixture) (*FixturedSmartContractBackend, error) {
+	if err := validateFixtures(contractRegistry, fixtures); err != nil {
+		return nil, stacktrace.Propagate(err, "The contract fixtures are invalid")
+	}
+
+	deployerAccountNames := []string{}
+	for _, fixture := range fixtures {
+		if fixture.Deployer != "" && !containsString(deployerAccountNames, fixture.Deployer) {
+			deployerAccountNames = append(deployerAccountNames, fixture.Deployer)
+		}
+	}
+	accounts := map[string]*networks_impl.FundedAccount{}
+	transactors := map[string]*networks_impl.NonceManagingTransactor{}
+	if len(deployerAccountNames) > 0 {
+		logrus.Infof("Funding contract fixture deployers %v...", deployerAccountNames)
+		fundedAccounts, err := backend.GetFundedAccounts(ctx, deployerAccountNames...)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred funding the contract fixture deployers")
+		}
+		for _, account := range fundedAccounts {
+			transactor, err := backend.NewAccountTransactor(account)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "An error occurred creating a transactor for account '%v'", account.Name)
+			}
+			accounts[account.Name] = account
+			transactors[account.Name] = transactor
+		}
+		logrus.Info("Contract fixture deployers funded")
+	}
+
+	client := backend.GetClient()
+	deploymentTxs := []*types.Transaction{}
+	deployedContracts := map[string]*DeployedContract{}
+	for _, fixture := range fixtures {
+		transactor := backend.GetNonceManagingTransactor()
+		if fixture.Deployer != "" {
+			transactor = transactors[fixture.Deployer]
+		}
+		var contract *contract_registry.DynamicContract
+		tx, err := transactor.Transact(ctx, fixture.Contract, deployMethodPrefix + fixture.Contract, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			var tx *types.Transaction
+			var err error
+			_, tx, contract, err = contractRegistry.Deploy(opts, client, fixture.Contract, fixture.ConstructorArgs...)
+			return tx, err
+		})
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred sending the deployment of contract fixture '%v'", fixture.Name)
+		}
+		deploymentTxs = append(deploymentTxs, tx)
+		deployedContracts[fixture.Name] = &DeployedContract{
+			Address:           contract.GetAddress(),
+			Contract:          contract,
+			Binding:           nil,
+			DeploymentReceipt: nil,
+		}
+	}
+
+	for i, fixture := range fixtures {
+		deployed := deployedContracts[fixture.Name]
+		receipt, err := backend.WaitForTransactionAccepted(ctx, deploymentTxs[i].Hash())
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred waiting for the deployment of contract fixture '%v' to be accepted", fixture.Name)
+		}
+		deployed.DeploymentReceipt = receipt
+		if fixture.Bind != nil {
+			binding, err := fixture.Bind(deployed.Address, client)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "An error occurred binding to contract fixture '%v'", fixture.Name)
+			}
+			deployed.Binding = binding
+		}
+		logrus.Infof("Contract fixture '%v' (%v) deployed at '%v'", fixture.Name, fixture.Contract, deployed.Address.Hex())
+	}
+	return newFixturedSmartContractBackend(backend, deployedContracts, accounts, transactors), nil
+}
+
+func validateFixtures(contractRegistry *contract_registry.ContractRegistry, fixtures []ContractFixture) error {
+	fixtureNames := map[string]bool{}
+	for _, fixture := range fixtures {
+		if strings.TrimSpace(fixture.Name) == "" {
+			return stacktrace.NewError("Contract fixture names may not be empty")
+		}
+		if fixtureNames[fixture.Name] {
+			return stacktrace.NewError("More than one contract fixture is named '%v'", fixture.Name)
+		}
+		fixtureNames[fixture.Name] = true
+		if _, err := contractRegistry.GetArtifact(fixture.Contract); err != nil {
+			return stacktrace.Propagate(err, "Contract fixture '%v' refers to a contract that isn't in the registry", fixture.Name)
+		}
+	}
+	return nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, candidate := range strs {
+		if candidate == str {
+			return true
+		}
+	}
+	return false
+}
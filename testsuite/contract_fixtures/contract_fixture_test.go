@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_fixtures_test
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/smart_contracts/bindings"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_fixtures"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	// Relative to this package's directory, which is where 'go test' runs tests from
+	contractArtifactsDirpath = "../../smart_contracts/artifacts"
+
+	deployerAccountName = "deployer"
+
+	numConcurrentDeployerTxs = 5
+)
+
+func TestDeployContractFixturesFromNamedDeployer(t *testing.T) {
+	backend, contractRegistry := newFixtureTestBackend(t)
+	defer backend.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), networks_impl.SimulatedTestRunTimeout)
+	defer cancelFunc()
+
+	fixtures := []contract_fixtures.ContractFixture{
+		{
+			Name:            "helloWorld",
+			Contract:        "HelloWorld",
+			ConstructorArgs: nil,
+			Deployer:        "",
+			Bind:            nil,
+		},
+		{
+			Name:            "firstStorage",
+			Contract:        "SimpleStorage",
+			ConstructorArgs: nil,
+			Deployer:        deployerAccountName,
+			Bind: func(address common.Address, backend bind.ContractBackend) (interface{}, error) {
+				return bindings.NewSimpleStorage(address, backend)
+			},
+		},
+		{
+			Name:            "secondStorage",
+			Contract:        "SimpleStorage",
+			ConstructorArgs: nil,
+			Deployer:        deployerAccountName,
+			Bind:            nil,
+		},
+	}
+	fixtured, err := contract_fixtures.DeployContractFixtures(ctx, backend, contractRegistry, fixtures)
+	if err != nil {
+		t.Fatalf("An error occurred deploying the contract fixtures: %v", err)
+	}
+
+	deployerAccount, err := fixtured.GetDeployerAccount(deployerAccountName)
+	if err != nil {
+		t.Fatalf("An error occurred getting the deployer account: %v", err)
+	}
+	// Contract addresses are derived from the deployer's address and nonce, which shows who deployed each fixture
+	expectedAddresses := map[string]common.Address{
+		"helloWorld":    crypto.CreateAddress(backend.GetNonceManagingTransactor().GetAddress(), 0),
+		"firstStorage":  crypto.CreateAddress(deployerAccount.Address, 0),
+		"secondStorage": crypto.CreateAddress(deployerAccount.Address, 1),
+	}
+	for fixtureName, expectedAddress := range expectedAddresses {
+		deployed, err := fixtured.GetDeployedContract(fixtureName)
+		if err != nil {
+			t.Fatalf("An error occurred getting contract fixture '%v': %v", fixtureName, err)
+		}
+		if deployed.Address != expectedAddress || deployed.Contract.GetAddress() != expectedAddress {
+			t.Errorf("Expected contract fixture '%v' to be deployed at '%v', but was at '%v'", fixtureName, expectedAddress.Hex(), deployed.Address.Hex())
+		}
+		if deployed.DeploymentReceipt == nil || deployed.DeploymentReceipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("Expected contract fixture '%v' to have a successful deployment receipt, but got %v", fixtureName, deployed.DeploymentReceipt)
+		}
+	}
+
+	storageDeployment, err := fixtured.GetDeployedContract("firstStorage")
+	if err != nil {
+		t.Fatalf("An error occurred getting the bound contract fixture: %v", err)
+	}
+	storageContract, ok := storageDeployment.Binding.(*bindings.SimpleStorage)
+	if !ok {
+		t.Fatalf("Expected the bound fixture's binding to be a *bindings.SimpleStorage, but got '%v'", storageDeployment.Binding)
+	}
+	unboundDeployment, err := fixtured.GetDeployedContract("secondStorage")
+	if err != nil {
+		t.Fatalf("An error occurred getting the unbound contract fixture: %v", err)
+	}
+	if unboundDeployment.Binding != nil {
+		t.Errorf("Expected a fixture without a BindFunc to have no binding, but got '%v'", unboundDeployment.Binding)
+	}
+
+	// The deployer's transactor already used two nonces, so sending through it must pick up after them, even when
+	//  sending concurrently
+	deployerTransactor, err := fixtured.GetDeployerTransactor(deployerAccountName)
+	if err != nil {
+		t.Fatalf("An error occurred getting the deployer's transactor: %v", err)
+	}
+	txs := make([]*types.Transaction, numConcurrentDeployerTxs)
+	errs := make([]error, numConcurrentDeployerTxs)
+	waitGroup := sync.WaitGroup{}
+	for i := 0; i < numConcurrentDeployerTxs; i++ {
+		waitGroup.Add(1)
+		go func(idx int) {
+			defer waitGroup.Done()
+			txs[idx], errs[idx] = deployerTransactor.Transact(ctx, "SimpleStorage", "Set", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+				return storageContract.Set(opts, big.NewInt(int64(idx)))
+			})
+		}(i)
+	}
+	waitGroup.Wait()
+	for i, tx := range txs {
+		if errs[i] != nil {
+			t.Fatalf("An error occurred sending transaction %v from the deployer: %v", i, errs[i])
+		}
+		if _, err := fixtured.WaitForTransactionAccepted(ctx, tx.Hash()); err != nil {
+			t.Fatalf("An error occurred waiting for transaction %v from the deployer to be accepted: %v", i, err)
+		}
+	}
+	deployerNonce, err := backend.GetClient().NonceAt(ctx, deployerAccount.Address, nil)
+	if err != nil {
+		t.Fatalf("An error occurred getting the deployer's nonce: %v", err)
+	}
+	if expectedNonce := uint64(2 + numConcurrentDeployerTxs); deployerNonce != expectedNonce {
+		t.Errorf("Expected the deployer to have sent %v transactions, but it sent %v", expectedNonce, deployerNonce)
+	}
+
+	if _, err := fixtured.GetDeployedContract("missing"); err == nil {
+		t.Errorf("Expected an error getting a contract fixture that wasn't declared, but got none")
+	}
+	if _, err := fixtured.GetDeployerAccount("stranger"); err == nil {
+		t.Errorf("Expected an error getting an account that didn't deploy any fixtures, but got none")
+	}
+	if _, err := fixtured.GetDeployerTransactor("stranger"); err == nil {
+		t.Errorf("Expected an error getting a transactor for an account that didn't deploy any fixtures, but got none")
+	}
+}
+
+// Invalid fixtures are rejected before anything gets sent
+func TestDeployContractFixturesRejectsInvalidFixtures(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		fixtures []contract_fixtures.ContractFixture
+
+		expectedErrSubstring string
+	}{
+		{
+			name:                 "Empty name",
+			fixtures:             []contract_fixtures.ContractFixture{newTestFixture("", "HelloWorld")},
+			expectedErrSubstring: "names may not be empty",
+		},
+		{
+			name:                 "Blank name",
+			fixtures:             []contract_fixtures.ContractFixture{newTestFixture("  ", "HelloWorld")},
+			expectedErrSubstring: "names may not be empty",
+		},
+		{
+			name: "Duplicate name",
+			fixtures: []contract_fixtures.ContractFixture{
+				newTestFixture("storage", "SimpleStorage"),
+				newTestFixture("storage", "HelloWorld"),
+			},
+			expectedErrSubstring: "More than one contract fixture is named 'storage'",
+		},
+		{
+			name:                 "Unknown contract",
+			fixtures:             []contract_fixtures.ContractFixture{newTestFixture("token", "Token")},
+			expectedErrSubstring: "Contract fixture 'token' refers to a contract that isn't in the registry",
+		},
+	}
+
+	backend, contractRegistry := newFixtureTestBackend(t)
+	defer backend.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), networks_impl.SimulatedTestRunTimeout)
+	defer cancelFunc()
+	for _, testCase := range testCases {
+		_, err := contract_fixtures.DeployContractFixtures(ctx, backend, contractRegistry, testCase.fixtures)
+		if err == nil {
+			t.Errorf("%v: expected an error deploying the fixtures, but got none", testCase.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), testCase.expectedErrSubstring) {
+			t.Errorf("%v: expected an error containing '%v', but got: %v", testCase.name, testCase.expectedErrSubstring, err)
+		}
+	}
+
+	transactorNonce, err := backend.GetClient().NonceAt(ctx, backend.GetNonceManagingTransactor().GetAddress(), nil)
+	if err != nil {
+		t.Fatalf("An error occurred getting the transactor's nonce: %v", err)
+	}
+	if transactorNonce != 0 {
+		t.Fatalf("Expected no transactions to be sent for invalid fixtures, but the transactor sent %v", transactorNonce)
+	}
+}
+
+func newFixtureTestBackend(t *testing.T) (*networks_impl.SimulatedSmartContractBackend, *contract_registry.ContractRegistry) {
+	contractRegistry, err := contract_registry.LoadContractRegistry(contractArtifactsDirpath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the contract registry: %v", err)
+	}
+	backend, _, err := networks_impl.NewSimulatedTestBackend()
+	if err != nil {
+		t.Fatalf("An error occurred creating the simulated backend: %v", err)
+	}
+	return backend, contractRegistry
+}
+
+func newTestFixture(name string, contract string) contract_fixtures.ContractFixture {
+	return contract_fixtures.ContractFixture{
+		Name:            name,
+		Contract:        contract,
+		ConstructorArgs: nil,
+		Deployer:        "",
+		Bind:            nil,
+	}
+}
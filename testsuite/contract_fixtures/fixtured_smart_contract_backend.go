@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_fixtures
+
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/palantir/stacktrace"
+	"sort"
+)
+
+// The network that a test's Setup returns once its contract fixtures are deployed, so that Run gets both the backend
+//  and the fixtures
+type FixturedSmartContractBackend struct {
+	networks_impl.SmartContractBackend
+
+	deployedContracts map[string]*DeployedContract
+
+	deployerAccounts map[string]*networks_impl.FundedAccount
+
+	// Only one transactor may exist per account, so the ones that deployed the fixtures are handed on to the test
+	deployerTransactors map[string]*networks_impl.NonceManagingTransactor
+}
+
+func newFixturedSmartContractBackend(
+		backend networks_impl.SmartContractBackend,
+		deployedContracts map[string]*DeployedContract,
+		deployerAccounts map[string]*networks_impl.FundedAccount,
+		deployerTransactors map[string]*networks_impl.NonceManagingTransactor) *FixturedSmartContractBackend {
+	return &FixturedSmartContractBackend{
+		SmartContractBackend: backend,
+		deployedContracts:    deployedContracts,
+		deployerAccounts:     deployerAccounts,
+		deployerTransactors:  deployerTransactors,
+	}
+}
+
+// Closes the wrapped backend
+func (fixtured FixturedSmartContractBackend) Close() error {
+	return networks_impl.CloseSmartContractBackend(fixtured.SmartContractBackend)
+}
+
+func (fixtured FixturedSmartContractBackend) GetDeployedContract(fixtureName string) (*DeployedContract, error) {
+	deployed, found := fixtured.deployedContracts[fixtureName]
+	if !found {
+		return nil, stacktrace.NewError("No contract fixture named '%v' was deployed; deployed fixtures are %v", fixtureName, fixtured.getFixtureNames())
+	}
+	return deployed, nil
+}
+
+// Returns an account that was created to deploy fixtures, by the name given as the fixtures' deployer
+func (fixtured FixturedSmartContractBackend) GetDeployerAccount(accountName string) (*networks_impl.FundedAccount, error) {
+	account, found := fixtured.deployerAccounts[accountName]
+	if !found {
+		return nil, stacktrace.NewError("No contract fixture was deployed by an account named '%v'", accountName)
+	}
+	return account, nil
+}
+
+// Returns the transactor that deployed fixtures from the named account, which the test must use for any further
+//  transactions from that account, else their nonces will collide
+func (fixtured FixturedSmartContractBackend) GetDeployerTransactor(accountName string) (*networks_impl.NonceManagingTransactor, error) {
+	transactor, found := fixtured.deployerTransactors[accountName]
+	if !found {
+		return nil, stacktrace.NewError("No contract fixture was deployed by an account named '%v'", accountName)
+	}
+	return transactor, nil
+}
+
+func (fixtured FixturedSmartContractBackend) getFixtureNames() []string {
+	result := []string{}
+	for name := range fixtured.deployedContracts {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_registry
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/palantir/stacktrace"
+	"strings"
+)
+
+const (
+	// solc's combined-json output keys contracts by "source path:contract name"
+	combinedJsonContractKeySeparator = ":"
+
+	// Unlinked library references in bytecode look like __$<hash>$__ (or __<path>:<name>__ in older solc versions)
+	unlinkedLibraryPlaceholderMarker = "__"
+)
+
+// The ABI and creation bytecode of a single compiled contract
+type ContractArtifact struct {
+	Name string
+
+	ABI abi.ABI
+
+	// Empty for interfaces and abstract contracts, which can't be deployed
+	Bytecode []byte
+
+	// Where the artifact was loaded from, for error messages
+	SourceFilepath string
+}
+
+// The shape of both solc's combined-json output (e.g. solc --combined-json abi,bin) and of Truffle/Hardhat artifacts; a
+//  file has either Contracts (combined-json) or ContractName/ABI/Bytecode (Truffle/Hardhat)
+type artifactFileJson struct {
+	Contracts map[string]combinedJsonContract `json:"contracts"`
+
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+	Bytecode     string          `json:"bytecode"`
+}
+
+type combinedJsonContract struct {
+	// solc < 0.8 emits the ABI as a JSON-encoded string, while newer versions emit it as an array
+	ABI json.RawMessage `json:"abi"`
+	Bin string          `json:"bin"`
+}
+
+// Parses every contract in a solc combined-json file, or the single contract in a Truffle/Hardhat artifact file
+func parseArtifactFile(filepath string, fileBytes []byte) ([]*ContractArtifact, error) {
+	fileJson := artifactFileJson{}
+	if err := json.Unmarshal(fileBytes, &fileJson); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred deserializing the artifact file JSON")
+	}
+
+	if fileJson.Contracts != nil {
+		result := []*ContractArtifact{}
+		for key, contract := range fileJson.Contracts {
+			keyParts := strings.Split(key, combinedJsonContractKeySeparator)
+			name := keyParts[len(keyParts) - 1]
+			artifact, err := newContractArtifact(name, contract.ABI, contract.Bin, filepath)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "An error occurred parsing combined-json contract '%v'", key)
+			}
+			result = append(result, artifact)
+		}
+		return result, nil
+	}
+
+	if fileJson.ContractName == "" || fileJson.ABI == nil {
+		return nil, stacktrace.NewError(
+			"The file is neither solc combined-json (no 'contracts' key) nor a Truffle/Hardhat artifact (no 'contractName' " +
+				"and 'abi' keys)")
+	}
+	artifact, err := newContractArtifact(fileJson.ContractName, fileJson.ABI, fileJson.Bytecode, filepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred parsing artifact for contract '%v'", fileJson.ContractName)
+	}
+	return []*ContractArtifact{artifact}, nil
+}
+
+func newContractArtifact(name string, abiJson json.RawMessage, bytecodeHex string, sourceFilepath string) (*ContractArtifact, error) {
+	// Unwrap ABIs that were emitted as a JSON-encoded string
+	var abiStr string
+	if err := json.Unmarshal(abiJson, &abiStr); err == nil {
+		abiJson = json.RawMessage(abiStr)
+	}
+	parsedAbi, err := abi.JSON(strings.NewReader(string(abiJson)))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred parsing the ABI")
+	}
+	if strings.Contains(bytecodeHex, unlinkedLibraryPlaceholderMarker) {
+		return nil, stacktrace.NewError("The bytecode references libraries that haven't been linked, which isn't supported")
+	}
+	return &ContractArtifact{
+		Name:           name,
+		ABI:            parsedAbi,
+		Bytecode:       common.FromHex(bytecodeHex),
+		SourceFilepath: sourceFilepath,
+	}, nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_registry
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	testArtifactFilepath = "/artifacts/test.json"
+
+	// A single view function, which is all the parsing tests need
+	testAbiJson = `[{"inputs":[],"name":"get","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+)
+
+func TestParseArtifactFile(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		fileJson string
+
+		// Contract name -> expected bytecode; ignored when an error is expected
+		expectedBytecodes map[string][]byte
+
+		// Empty when no error is expected
+		expectedErrSubstring string
+	}{
+		{
+			name:     "Combined-json with string ABI",
+			fileJson: `{"contracts": {"contracts/storage.sol:Storage": {"abi": ` + quoteJson(testAbiJson) + `, "bin": "6080"}}, "version": "0.7.6"}`,
+			expectedBytecodes: map[string][]byte{
+				"Storage": {0x60, 0x80},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:     "Combined-json with array ABI",
+			fileJson: `{"contracts": {"contracts/storage.sol:Storage": {"abi": ` + testAbiJson + `, "bin": "6080"}}, "version": "0.8.4"}`,
+			expectedBytecodes: map[string][]byte{
+				"Storage": {0x60, 0x80},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name: "Combined-json with several contracts",
+			fileJson: `{"contracts": {` +
+				`"contracts/storage.sol:Storage": {"abi": ` + testAbiJson + `, "bin": "6080"}, ` +
+				`"contracts/other.sol:Other": {"abi": ` + testAbiJson + `, "bin": "6040"}}}`,
+			expectedBytecodes: map[string][]byte{
+				"Storage": {0x60, 0x80},
+				"Other":   {0x60, 0x40},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:     "Combined-json key without a path",
+			fileJson: `{"contracts": {"Storage": {"abi": ` + testAbiJson + `, "bin": "6080"}}}`,
+			expectedBytecodes: map[string][]byte{
+				"Storage": {0x60, 0x80},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:     "Combined-json with empty bytecode",
+			fileJson: `{"contracts": {"contracts/storage.sol:IStorage": {"abi": ` + testAbiJson + `, "bin": ""}}}`,
+			expectedBytecodes: map[string][]byte{
+				"IStorage": {},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:     "Combined-json with missing bytecode",
+			fileJson: `{"contracts": {"contracts/storage.sol:IStorage": {"abi": ` + testAbiJson + `}}}`,
+			expectedBytecodes: map[string][]byte{
+				"IStorage": {},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:     "Truffle/Hardhat artifact",
+			fileJson: `{"contractName": "Storage", "abi": ` + testAbiJson + `, "bytecode": "0x6080", "deployedBytecode": "0x6040"}`,
+			expectedBytecodes: map[string][]byte{
+				"Storage": {0x60, 0x80},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:     "Truffle/Hardhat artifact with empty bytecode",
+			fileJson: `{"contractName": "IStorage", "abi": ` + testAbiJson + `, "bytecode": "0x"}`,
+			expectedBytecodes: map[string][]byte{
+				"IStorage": {},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:     "Truffle/Hardhat artifact with missing bytecode",
+			fileJson: `{"contractName": "IStorage", "abi": ` + testAbiJson + `}`,
+			expectedBytecodes: map[string][]byte{
+				"IStorage": {},
+			},
+			expectedErrSubstring: "",
+		},
+		{
+			name:                 "Combined-json with unlinked library",
+			fileJson:             `{"contracts": {"contracts/storage.sol:Storage": {"abi": ` + testAbiJson + `, "bin": "6080__$1234567890abcdef1234567890abcdef12$__6040"}}}`,
+			expectedBytecodes:    nil,
+			expectedErrSubstring: "libraries that haven't been linked",
+		},
+		{
+			name:                 "Truffle/Hardhat artifact with old-style unlinked library",
+			fileJson:             `{"contractName": "Storage", "abi": ` + testAbiJson + `, "bytecode": "0x6080__contracts/math.sol:Math______________6040"}`,
+			expectedBytecodes:    nil,
+			expectedErrSubstring: "libraries that haven't been linked",
+		},
+		{
+			name:                 "Invalid ABI",
+			fileJson:             `{"contractName": "Storage", "abi": {"type": "function", "name": "get"}, "bytecode": "0x6080"}`,
+			expectedBytecodes:    nil,
+			expectedErrSubstring: "An error occurred parsing the ABI",
+		},
+		{
+			name:                 "Truffle/Hardhat artifact without an ABI",
+			fileJson:             `{"contractName": "Storage", "bytecode": "0x6080"}`,
+			expectedBytecodes:    nil,
+			expectedErrSubstring: "neither solc combined-json",
+		},
+		{
+			name:                 "Neither format",
+			fileJson:             `{"compiler": {"version": "0.7.6"}}`,
+			expectedBytecodes:    nil,
+			expectedErrSubstring: "neither solc combined-json",
+		},
+		{
+			name:                 "Invalid JSON",
+			fileJson:             `{"contracts": `,
+			expectedBytecodes:    nil,
+			expectedErrSubstring: "An error occurred deserializing the artifact file JSON",
+		},
+	}
+
+	for _, testCase := range testCases {
+		artifacts, err := parseArtifactFile(testArtifactFilepath, []byte(testCase.fileJson))
+		if testCase.expectedErrSubstring != "" {
+			if err == nil {
+				t.Errorf("%v: expected an error parsing the artifact file, but got none", testCase.name)
+			} else if !strings.Contains(err.Error(), testCase.expectedErrSubstring) {
+				t.Errorf("%v: expected an error containing '%v', but got: %v", testCase.name, testCase.expectedErrSubstring, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: expected no error parsing the artifact file, but got: %v", testCase.name, err)
+			continue
+		}
+
+		expectedNames := []string{}
+		for name := range testCase.expectedBytecodes {
+			expectedNames = append(expectedNames, name)
+		}
+		sort.Strings(expectedNames)
+		actualNames := []string{}
+		for _, artifact := range artifacts {
+			actualNames = append(actualNames, artifact.Name)
+		}
+		sort.Strings(actualNames)
+		if strings.Join(actualNames, ",") != strings.Join(expectedNames, ",") {
+			t.Errorf("%v: expected contracts %v, but got %v", testCase.name, expectedNames, actualNames)
+			continue
+		}
+
+		for _, artifact := range artifacts {
+			expectedBytecode := testCase.expectedBytecodes[artifact.Name]
+			if !bytes.Equal(artifact.Bytecode, expectedBytecode) {
+				t.Errorf("%v: expected contract '%v' to have bytecode %x, but got %x", testCase.name, artifact.Name, expectedBytecode, artifact.Bytecode)
+			}
+			if _, found := artifact.ABI.Methods["get"]; !found {
+				t.Errorf("%v: expected contract '%v' to have a 'get' method in its ABI, but it didn't", testCase.name, artifact.Name)
+			}
+			if artifact.SourceFilepath != testArtifactFilepath {
+				t.Errorf("%v: expected contract '%v' to come from '%v', but got '%v'", testCase.name, artifact.Name, testArtifactFilepath, artifact.SourceFilepath)
+			}
+		}
+	}
+}
+
+// Encodes the string as a JSON string, the way solc < 0.8 embeds ABIs in combined-json
+func quoteJson(str string) string {
+	return `"` + strings.Replace(str, `"`, `\"`, -1) + `"`
+}
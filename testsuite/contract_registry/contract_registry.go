@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_registry
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	artifactFileExt = ".json"
+)
+
+// Holds compiled contracts loaded from artifact files, so that contracts can be deployed and called by name without
+//  generated Go bindings
+type ContractRegistry struct {
+	artifacts map[string]*ContractArtifact
+}
+
+// Loads every solc combined-json and Truffle/Hardhat artifact file (*.json) under the given directory, recursively
+// Contract names must be unique across all the files
+func LoadContractRegistry(dirpath string) (*ContractRegistry, error) {
+	artifacts := map[string]*ContractArtifact{}
+	walkFunc := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred walking to '%v'", path)
+		}
+		if info.IsDir() || filepath.Ext(path) != artifactFileExt {
+			return nil
+		}
+		fileBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred reading artifact file '%v'", path)
+		}
+		fileArtifacts, err := parseArtifactFile(path, fileBytes)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred parsing artifact file '%v'", path)
+		}
+		for _, artifact := range fileArtifacts {
+			if existing, found := artifacts[artifact.Name]; found {
+				return stacktrace.NewError(
+					"Contract '%v' is defined in both '%v' and '%v'",
+					artifact.Name,
+					existing.SourceFilepath,
+					artifact.SourceFilepath)
+			}
+			artifacts[artifact.Name] = artifact
+		}
+		return nil
+	}
+	if err := filepath.Walk(dirpath, walkFunc); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred loading contract artifacts from directory '%v'", dirpath)
+	}
+
+	result := &ContractRegistry{artifacts: artifacts}
+	logrus.Debugf("Loaded contracts %v from artifacts directory '%v'", result.GetContractNames(), dirpath)
+	return result, nil
+}
+
+// Returns the names of every contract in the registry, sorted
+func (registry ContractRegistry) GetContractNames() []string {
+	result := []string{}
+	for name := range registry.artifacts {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (registry ContractRegistry) GetArtifact(contractName string) (*ContractArtifact, error) {
+	artifact, found := registry.artifacts[contractName]
+	if !found {
+		return nil, stacktrace.NewError(
+			"No contract named '%v' exists in the registry; known contracts are %v",
+			contractName,
+			registry.GetContractNames())
+	}
+	return artifact, nil
+}
+
+// Deploys the named contract with the given constructor arguments, which must be Go values of the types that the
+//  abi package expects (e.g. *big.Int for uint256, common.Address for address)
+// This has the same shape as the generated DeployXXXXX binding functions, so it can be used the same way
+func (registry ContractRegistry) Deploy(
+		opts *bind.TransactOpts,
+		backend bind.ContractBackend,
+		contractName string,
+		constructorArgs ...interface{}) (common.Address, *types.Transaction, *DynamicContract, error) {
+	artifact, err := registry.GetArtifact(contractName)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if len(artifact.Bytecode) == 0 {
+		return common.Address{}, nil, nil, stacktrace.NewError(
+			"Contract '%v' from '%v' has no bytecode, so it can't be deployed; is it an interface or abstract contract?",
+			contractName,
+			artifact.SourceFilepath)
+	}
+	address, tx, _, err := bind.DeployContract(opts, artifact.ABI, artifact.Bytecode, backend, constructorArgs...)
+	if err != nil {
+		return common.Address{}, nil, nil, stacktrace.Propagate(err, "An error occurred deploying contract '%v'", contractName)
+	}
+	return address, tx, newDynamicContract(artifact, address, backend), nil
+}
+
+// Binds to an instance of the named contract that has already been deployed at the given address
+func (registry ContractRegistry) Bind(contractName string, address common.Address, backend bind.ContractBackend) (*DynamicContract, error) {
+	artifact, err := registry.GetArtifact(contractName)
+	if err != nil {
+		return nil, err
+	}
+	return newDynamicContract(artifact, address, backend), nil
+}
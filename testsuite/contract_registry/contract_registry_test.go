@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_registry_test
+
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	// Relative to this package's directory, which is where 'go test' runs tests from
+	contractArtifactsDirpath = "../../smart_contracts/artifacts"
+
+	testAbiJson = `[{"inputs":[],"name":"get","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+	storageCombinedJson         = `{"contracts": {"contracts/storage.sol:Storage": {"abi": ` + testAbiJson + `, "bin": "6080"}}}`
+	storageTruffleJson          = `{"contractName": "Storage", "abi": ` + testAbiJson + `, "bytecode": "0x6080"}`
+	otherTruffleJson            = `{"contractName": "Other", "abi": ` + testAbiJson + `, "bytecode": "0x6080"}`
+	storageInterfaceTruffleJson = `{"contractName": "IStorage", "abi": ` + testAbiJson + `, "bytecode": "0x"}`
+)
+
+func TestLoadContractRegistryLoadsCommittedArtifacts(t *testing.T) {
+	registry, err := contract_registry.LoadContractRegistry(contractArtifactsDirpath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the committed contract artifacts: %v", err)
+	}
+	expectedNames := []string{"HelloWorld", "SimpleStorage"}
+	if actualNames := registry.GetContractNames(); strings.Join(actualNames, ",") != strings.Join(expectedNames, ",") {
+		t.Fatalf("Expected contracts %v, but got %v", expectedNames, actualNames)
+	}
+	for _, name := range expectedNames {
+		artifact, err := registry.GetArtifact(name)
+		if err != nil {
+			t.Fatalf("An error occurred getting the artifact for contract '%v': %v", name, err)
+		}
+		if len(artifact.Bytecode) == 0 {
+			t.Errorf("Expected committed contract '%v' to have bytecode, but it had none", name)
+		}
+	}
+	if _, err := registry.GetArtifact("Token"); err == nil {
+		t.Errorf("Expected an error getting a contract that isn't in the registry, but got none")
+	}
+}
+
+func TestLoadContractRegistry(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		// Filepath relative to the artifacts directory -> contents
+		files map[string]string
+
+		// Ignored when an error is expected
+		expectedNames []string
+
+		// Empty when no error is expected
+		expectedErrSubstring string
+	}{
+		{
+			name: "Artifacts in subdirectories",
+			files: map[string]string{
+				"storage.json":      storageCombinedJson,
+				"nested/other.json": otherTruffleJson,
+				"nested/README.md":  "Not an artifact",
+			},
+			expectedNames:        []string{"Other", "Storage"},
+			expectedErrSubstring: "",
+		},
+		{
+			name: "Duplicate contract name in the same format",
+			files: map[string]string{
+				"first.json":  storageTruffleJson,
+				"second.json": storageTruffleJson,
+			},
+			expectedNames:        nil,
+			expectedErrSubstring: "Contract 'Storage' is defined in both",
+		},
+		{
+			name: "Duplicate contract name across formats",
+			files: map[string]string{
+				"combined.json":       storageCombinedJson,
+				"nested/storage.json": storageTruffleJson,
+			},
+			expectedNames:        nil,
+			expectedErrSubstring: "Contract 'Storage' is defined in both",
+		},
+		{
+			name: "Invalid artifact file",
+			files: map[string]string{
+				"storage.json": storageCombinedJson,
+				"broken.json":  `{"compiler": {"version": "0.7.6"}}`,
+			},
+			expectedNames:        nil,
+			expectedErrSubstring: "An error occurred parsing artifact file",
+		},
+	}
+
+	for _, testCase := range testCases {
+		dirpath := writeTestArtifactFiles(t, testCase.files)
+		registry, err := contract_registry.LoadContractRegistry(dirpath)
+		os.RemoveAll(dirpath)
+		if testCase.expectedErrSubstring != "" {
+			if err == nil {
+				t.Errorf("%v: expected an error loading the contract registry, but got none", testCase.name)
+			} else if !strings.Contains(err.Error(), testCase.expectedErrSubstring) {
+				t.Errorf("%v: expected an error containing '%v', but got: %v", testCase.name, testCase.expectedErrSubstring, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: expected no error loading the contract registry, but got: %v", testCase.name, err)
+			continue
+		}
+		if actualNames := registry.GetContractNames(); strings.Join(actualNames, ",") != strings.Join(testCase.expectedNames, ",") {
+			t.Errorf("%v: expected contracts %v, but got %v", testCase.name, testCase.expectedNames, actualNames)
+		}
+	}
+}
+
+// Interfaces and abstract contracts load fine, but deploying them is refused before anything gets sent
+func TestDeployRejectsContractsWithoutBytecode(t *testing.T) {
+	dirpath := writeTestArtifactFiles(t, map[string]string{"storage.json": storageInterfaceTruffleJson})
+	defer os.RemoveAll(dirpath)
+	registry, err := contract_registry.LoadContractRegistry(dirpath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the contract registry: %v", err)
+	}
+
+	_, _, _, err = registry.Deploy(nil, nil, "IStorage")
+	if err == nil {
+		t.Fatalf("Expected an error deploying a contract without bytecode, but got none")
+	}
+	if !strings.Contains(err.Error(), "has no bytecode") {
+		t.Errorf("Expected an error saying the contract has no bytecode, but got: %v", err)
+	}
+}
+
+// Writes the files to a new temporary directory, which the caller must remove
+func writeTestArtifactFiles(t *testing.T, files map[string]string) string {
+	dirpath, err := ioutil.TempDir("", "contract-registry-test")
+	if err != nil {
+		t.Fatalf("An error occurred creating a temporary artifacts directory: %v", err)
+	}
+	for relativeFilepath, contents := range files {
+		filepathToWrite := filepath.Join(dirpath, relativeFilepath)
+		if err := os.MkdirAll(filepath.Dir(filepathToWrite), 0755); err != nil {
+			os.RemoveAll(dirpath)
+			t.Fatalf("An error occurred creating the directory for artifact file '%v': %v", relativeFilepath, err)
+		}
+		if err := ioutil.WriteFile(filepathToWrite, []byte(contents), 0644); err != nil {
+			os.RemoveAll(dirpath)
+			t.Fatalf("An error occurred writing artifact file '%v': %v", relativeFilepath, err)
+		}
+	}
+	return dirpath
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_registry
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/palantir/stacktrace"
+)
+
+// A deployed contract whose methods are called by name, with arguments and results packed and unpacked by the abi
+//  package according to the contract's ABI
+type DynamicContract struct {
+	artifact *ContractArtifact
+
+	address common.Address
+
+	backend bind.ContractBackend
+
+	boundContract *bind.BoundContract
+}
+
+func newDynamicContract(artifact *ContractArtifact, address common.Address, backend bind.ContractBackend) *DynamicContract {
+	return &DynamicContract{
+		artifact:      artifact,
+		address:       address,
+		backend:       backend,
+		boundContract: bind.NewBoundContract(address, artifact.ABI, backend, backend, backend),
+	}
+}
+
+func (contract DynamicContract) GetName() string {
+	return contract.artifact.Name
+}
+
+func (contract DynamicContract) GetAddress() common.Address {
+	return contract.address
+}
+
+func (contract DynamicContract) GetArtifact() *ContractArtifact {
+	return contract.artifact
+}
+
+// Sends a transaction calling the given method; args must be Go values of the types that the abi package expects
+func (contract DynamicContract) Transact(opts *bind.TransactOpts, methodName string, args ...interface{}) (*types.Transaction, error) {
+	if _, found := contract.artifact.ABI.Methods[methodName]; !found {
+		return nil, stacktrace.NewError("Contract '%v' has no method '%v'", contract.artifact.Name, methodName)
+	}
+	tx, err := contract.boundContract.Transact(opts, methodName, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred sending a transaction calling '%v.%v'", contract.artifact.Name, methodName)
+	}
+	return tx, nil
+}
+
+// Calls the given method without sending a transaction, returning its outputs in order as the Go types that the abi
+//  package unpacks them to (e.g. *big.Int for uint256)
+func (contract DynamicContract) Call(opts *bind.CallOpts, methodName string, args ...interface{}) ([]interface{}, error) {
+	method, found := contract.artifact.ABI.Methods[methodName]
+	if !found {
+		return nil, stacktrace.NewError("Contract '%v' has no method '%v'", contract.artifact.Name, methodName)
+	}
+	if opts == nil {
+		opts = &bind.CallOpts{}
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	input, err := contract.artifact.ABI.Pack(methodName, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred packing the arguments for '%v.%v'", contract.artifact.Name, methodName)
+	}
+	callMsg := ethereum.CallMsg{
+		From: opts.From,
+		To:   &contract.address,
+		Data: input,
+	}
+	output, err := contract.backend.CallContract(ctx, callMsg, opts.BlockNumber)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred calling '%v.%v'", contract.artifact.Name, methodName)
+	}
+	if len(output) == 0 && len(method.Outputs) > 0 {
+		// Mirror the generated bindings, which report a missing contract rather than a confusing unpacking error
+		code, err := contract.backend.CodeAt(ctx, contract.address, opts.BlockNumber)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred checking for code at '%v'", contract.address.Hex())
+		}
+		if len(code) == 0 {
+			return nil, stacktrace.Propagate(bind.ErrNoCode, "Can't call '%v.%v'", contract.artifact.Name, methodName)
+		}
+	}
+	results, err := method.Outputs.UnpackValues(output)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred unpacking the results of '%v.%v'", contract.artifact.Name, methodName)
+	}
+	return results, nil
+}
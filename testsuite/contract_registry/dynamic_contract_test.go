@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_registry_test
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const (
+	simpleStorageContractName = "SimpleStorage"
+
+	storedValue = 42
+)
+
+func TestDynamicContractTransactAndCall(t *testing.T) {
+	backend, registry := newDynamicContractTestBackend(t)
+	defer backend.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), networks_impl.SimulatedTestRunTimeout)
+	defer cancelFunc()
+	transactor := backend.GetNonceManagingTransactor()
+
+	var storageContract *contract_registry.DynamicContract
+	deployTx, err := transactor.Transact(ctx, simpleStorageContractName, "Deploy", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		var tx *types.Transaction
+		var err error
+		_, tx, storageContract, err = registry.Deploy(opts, backend.GetClient(), simpleStorageContractName)
+		return tx, err
+	})
+	if err != nil {
+		t.Fatalf("An error occurred deploying the contract: %v", err)
+	}
+	if _, err := backend.WaitForTransactionAccepted(ctx, deployTx.Hash()); err != nil {
+		t.Fatalf("An error occurred waiting for the contract deployment to be accepted: %v", err)
+	}
+	if storageContract.GetName() != simpleStorageContractName {
+		t.Errorf("Expected the deployed contract to be named '%v', but was '%v'", simpleStorageContractName, storageContract.GetName())
+	}
+
+	initialResults, err := storageContract.Call(nil, "get")
+	if err != nil {
+		t.Fatalf("An error occurred calling the contract before setting a value: %v", err)
+	}
+	assertSingleUintResult(t, initialResults, 0)
+
+	setTx, err := transactor.Transact(ctx, simpleStorageContractName, "set", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return storageContract.Transact(opts, "set", big.NewInt(storedValue))
+	})
+	if err != nil {
+		t.Fatalf("An error occurred setting the value: %v", err)
+	}
+	if _, err := backend.WaitForTransactionAccepted(ctx, setTx.Hash()); err != nil {
+		t.Fatalf("An error occurred waiting for the value to be set: %v", err)
+	}
+
+	// A contract bound by name to the deployed address sees the same state
+	boundContract, err := registry.Bind(simpleStorageContractName, storageContract.GetAddress(), backend.GetClient())
+	if err != nil {
+		t.Fatalf("An error occurred binding to the deployed contract: %v", err)
+	}
+	for _, methodName := range []string{"get", "num"} {
+		results, err := boundContract.Call(&bind.CallOpts{Context: ctx}, methodName)
+		if err != nil {
+			t.Fatalf("An error occurred calling '%v' after setting the value: %v", methodName, err)
+		}
+		assertSingleUintResult(t, results, storedValue)
+	}
+}
+
+func TestDynamicContractRejectsBadCalls(t *testing.T) {
+	backend, registry := newDynamicContractTestBackend(t)
+	defer backend.Close()
+
+	// Nothing is deployed at this address
+	undeployedContract, err := registry.Bind(simpleStorageContractName, common.HexToAddress("0x1234"), backend.GetClient())
+	if err != nil {
+		t.Fatalf("An error occurred binding to the undeployed contract: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+
+		call func() error
+
+		expectedErrSubstring string
+	}{
+		{
+			name: "Call unknown method",
+			call: func() error {
+				_, err := undeployedContract.Call(nil, "increment")
+				return err
+			},
+			expectedErrSubstring: "has no method 'increment'",
+		},
+		{
+			name: "Transact unknown method",
+			call: func() error {
+				_, err := undeployedContract.Transact(&bind.TransactOpts{}, "increment")
+				return err
+			},
+			expectedErrSubstring: "has no method 'increment'",
+		},
+		{
+			name: "Call with wrong argument type",
+			call: func() error {
+				_, err := undeployedContract.Call(nil, "set", "forty-two")
+				return err
+			},
+			expectedErrSubstring: "An error occurred packing the arguments",
+		},
+		{
+			name: "Call undeployed contract",
+			call: func() error {
+				_, err := undeployedContract.Call(nil, "get")
+				return err
+			},
+			expectedErrSubstring: bind.ErrNoCode.Error(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.call()
+		if err == nil {
+			t.Errorf("%v: expected an error, but got none", testCase.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), testCase.expectedErrSubstring) {
+			t.Errorf("%v: expected an error containing '%v', but got: %v", testCase.name, testCase.expectedErrSubstring, err)
+		}
+	}
+}
+
+func newDynamicContractTestBackend(t *testing.T) (*networks_impl.SimulatedSmartContractBackend, *contract_registry.ContractRegistry) {
+	registry, err := contract_registry.LoadContractRegistry(contractArtifactsDirpath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the contract registry: %v", err)
+	}
+	backend, _, err := networks_impl.NewSimulatedTestBackend()
+	if err != nil {
+		t.Fatalf("An error occurred creating the simulated backend: %v", err)
+	}
+	return backend, registry
+}
+
+func assertSingleUintResult(t *testing.T, results []interface{}, expected int64) {
+	if len(results) != 1 {
+		t.Fatalf("Expected a single result, but got %v", results)
+	}
+	value, ok := results[0].(*big.Int)
+	if !ok {
+		t.Fatalf("Expected the result to be a *big.Int, but got '%v'", results[0])
+	}
+	if value.Cmp(big.NewInt(expected)) != 0 {
+		t.Errorf("Expected the result to be %v, but got %v", expected, value)
+	}
+}
@@ -5,6 +5,56 @@
 
 package execution_impl
 
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
+)
+
 type SmartContractTestsuiteArgs struct {
 	AvalancheImage string	`json:"avalancheImage"`
+
+	// Non-bootstrap nodes to add to each test network, on top of the default bootstrap stakers; they aren't validators
+	NumAdditionalNodes int	`json:"numAdditionalNodes"`
+
+	// Fields missing from the params JSON keep the defaults from networks_impl.NewDefaultSnowParams
+	SnowParams networks_impl.SnowParams	`json:"snowParams"`
+
+	// Fields missing from the params JSON keep the defaults from networks_impl.NewDefaultNodePorts
+	NodePorts networks_impl.NodePorts	`json:"nodePorts"`
+
+	// How long to wait for each node to become available during network setup
+	NodeStartupPollIntervalSeconds int	`json:"nodeStartupPollIntervalSeconds"`
+	MaxNumNodeStartupPolls int	`json:"maxNumNodeStartupPolls"`
+
+	// Balance given to every account created through SmartContractAvalancheNetwork.GetFundedAccounts
+	FundedAccountBalanceAvax uint64	`json:"fundedAccountBalanceAvax"`
+
+	// Set a mnemonic or seed here to get the same funded account addresses, and therefore contract addresses, on every run
+	AccountKeys networks_impl.AccountKeySource	`json:"accountKeys"`
+
+	// Fields missing from the params JSON keep the defaults from networks_impl.NewDefaultGasStrategy
+	GasStrategy networks_impl.GasStrategy	`json:"gasStrategy"`
+
+	// Directory that each test's gas usage report gets written to; if empty, the reports are only logged
+	GasReportDirpath string	`json:"gasReportDirpath"`
+
+	// If set, tests fail when their gas usage exceeds the baseline in this file by more than its tolerance
+	GasBaselineFilepath string	`json:"gasBaselineFilepath"`
+
+	// Directory of solc combined-json or Truffle/Hardhat artifacts for contracts that tests deploy and call by name
+	ContractArtifactsDirpath string	`json:"contractArtifactsDirpath"`
+
+	// Directory of JSON/YAML scenario files, each of which becomes a test; if empty, no scenario tests are added
+	ScenariosDirpath string	`json:"scenariosDirpath"`
+
+	// Selects which tests the suite runs by name and tag, e.g. only the "smoke" tests on every commit; if empty, every
+	//  registered test runs
+	TestFilter test_catalog.TestFilterConfig	`json:"testFilter"`
+
+	// Fields missing from the params JSON keep the defaults from test_catalog.NewDefaultTimeoutConfig
+	Timeouts test_catalog.TimeoutConfig	`json:"timeouts"`
+
+	// Set an RPC URL and funded key here to run the tests against an existing C-Chain node instead of setting up an
+	//  Avalanche network in Kurtosis
+	ExternalRpc networks_impl.ExternalRpcConfig	`json:"externalRpc"`
 }
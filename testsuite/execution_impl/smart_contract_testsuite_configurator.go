@@ -7,11 +7,28 @@ package execution_impl
 
 import (
 	"encoding/json"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
 	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/testsuite_impl"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/testsuite_impl/contract_scenario"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
+	"math"
 	"strings"
+	"time"
+)
+
+const (
+	defaultFundedAccountBalanceAvax = 1000
+
+	// Relative to the testsuite container's working directory, where the Dockerfile copies the artifacts to
+	defaultContractArtifactsDirpath = "smart_contracts/artifacts"
+
+	// Relative to the testsuite container's working directory, where the Dockerfile copies the scenarios to
+	defaultScenariosDirpath = "smart_contracts/scenarios"
 )
 
 type SmartContractTestsuiteConfigurator struct {}
@@ -35,7 +52,18 @@ func (t SmartContractTestsuiteConfigurator) SetLogLevel(logLevelStr string) erro
 
 func (t SmartContractTestsuiteConfigurator) ParseParamsAndCreateSuite(paramsJsonStr string) (testsuite.TestSuite, error) {
 	paramsJsonBytes := []byte(paramsJsonStr)
-	var args SmartContractTestsuiteArgs
+	defaultNodeStartupPolicy := networks_impl.NewDefaultNodeStartupPolicy()
+	args := SmartContractTestsuiteArgs{
+		SnowParams:                     networks_impl.NewDefaultSnowParams(),
+		NodePorts:                      networks_impl.NewDefaultNodePorts(),
+		NodeStartupPollIntervalSeconds: int(defaultNodeStartupPolicy.TimeBetweenPolls / time.Second),
+		MaxNumNodeStartupPolls:         defaultNodeStartupPolicy.MaxNumPolls,
+		FundedAccountBalanceAvax:       defaultFundedAccountBalanceAvax,
+		GasStrategy:                    networks_impl.NewDefaultGasStrategy(),
+		ContractArtifactsDirpath:       defaultContractArtifactsDirpath,
+		ScenariosDirpath:               defaultScenariosDirpath,
+		Timeouts:                       test_catalog.NewDefaultTimeoutConfig(),
+	}
 	if err := json.Unmarshal(paramsJsonBytes, &args); err != nil {
 		return nil, stacktrace.Propagate(err, "An error occurred deserializing the testsuite params JSON")
 	}
@@ -44,13 +72,109 @@ func (t SmartContractTestsuiteConfigurator) ParseParamsAndCreateSuite(paramsJson
 		return nil, stacktrace.Propagate(err, "An error occurred validating the deserialized testsuite params")
 	}
 
-	suite := testsuite_impl.NewSmartContractTestsuite(args.AvalancheImage)
+	networkConfig := networks_impl.SmartContractAvalancheNetworkConfig{
+		AvalancheImage:       args.AvalancheImage,
+		NumAdditionalNodes:   args.NumAdditionalNodes,
+		Snow:                 args.SnowParams,
+		NodePorts:            args.NodePorts,
+		NodeStartup:          getNodeStartupPolicy(args),
+		FundedAccountBalance: args.FundedAccountBalanceAvax * units.Avax,
+		AccountKeys:          args.AccountKeys,
+		Gas:                  args.GasStrategy,
+		ExternalRpc:          args.ExternalRpc,
+	}
+	gasReportConfig := networks_impl.GasReportConfig{
+		ReportDirpath: args.GasReportDirpath,
+		Baseline:      nil,
+	}
+	if args.GasBaselineFilepath != "" {
+		baseline, err := networks_impl.LoadGasBaseline(args.GasBaselineFilepath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred loading the gas baseline")
+		}
+		gasReportConfig.Baseline = baseline
+	}
+	contractRegistry, err := contract_registry.LoadContractRegistry(args.ContractArtifactsDirpath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred loading the contract registry")
+	}
+	// Scenarios deploy contracts from the registry, so can only be checked once it's loaded
+	if args.ScenariosDirpath != "" {
+		if err := contract_scenario.RegisterScenarioTests(args.ScenariosDirpath, contractRegistry); err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred registering the scenario tests")
+		}
+	}
+	// Scenario tests can have their timeouts overridden too, so this has to wait until they're registered
+	if err := args.Timeouts.ValidateTestNames(); err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid timeout overrides")
+	}
+	// The timeouts are filled in per test, from each test's metadata and the timeout config
+	testDependencies := test_catalog.TestDependencies{
+		NetworkConfig:    networkConfig,
+		GasReportConfig:  gasReportConfig,
+		ContractRegistry: contractRegistry,
+		SetupTimeout:     0,
+		RunTimeout:       0,
+	}
+	testFilter, err := test_catalog.NewTestFilter(args.TestFilter)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred creating the test filter")
+	}
+	suite := testsuite_impl.NewSmartContractTestsuite(testDependencies, testFilter, args.Timeouts)
 	return suite, nil
 }
 
 func validateArgs(args SmartContractTestsuiteArgs) error {
-	if strings.TrimSpace(args.AvalancheImage) == "" {
+	if err := args.ExternalRpc.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid external RPC config")
+	}
+	// No Avalanche network gets set up when running against an external node, so no image is needed
+	if !args.ExternalRpc.IsEnabled() && strings.TrimSpace(args.AvalancheImage) == "" {
 		return stacktrace.NewError("Avalanche image is empty")
 	}
+	if args.NumAdditionalNodes < 0 {
+		return stacktrace.NewError("Number of additional nodes must be >= 0, but was %v", args.NumAdditionalNodes)
+	}
+	if err := args.SnowParams.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid Snow consensus params")
+	}
+	if err := args.NodePorts.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid node ports")
+	}
+	if err := getNodeStartupPolicy(args).Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid node startup policy")
+	}
+	if args.FundedAccountBalanceAvax == 0 {
+		return stacktrace.NewError("Funded account balance must be > 0 AVAX")
+	}
+	// The balance gets converted to nAVAX, which would otherwise silently wrap around
+	if args.FundedAccountBalanceAvax > math.MaxUint64 / units.Avax {
+		return stacktrace.NewError(
+			"Funded account balance must be <= %v AVAX, but was %v",
+			uint64(math.MaxUint64 / units.Avax),
+			args.FundedAccountBalanceAvax)
+	}
+	if err := args.AccountKeys.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid account key source")
+	}
+	if err := args.GasStrategy.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid gas strategy")
+	}
+	if strings.TrimSpace(args.ContractArtifactsDirpath) == "" {
+		return stacktrace.NewError("Contract artifacts dirpath is empty")
+	}
+	if _, err := test_catalog.NewTestFilter(args.TestFilter); err != nil {
+		return stacktrace.Propagate(err, "Invalid test filter")
+	}
+	if err := args.Timeouts.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid timeouts")
+	}
 	return nil
 }
+
+func getNodeStartupPolicy(args SmartContractTestsuiteArgs) networks_impl.NodeStartupPolicy {
+	return networks_impl.NodeStartupPolicy{
+		TimeBetweenPolls: time.Duration(args.NodeStartupPollIntervalSeconds) * time.Second,
+		MaxNumPolls:      args.MaxNumNodeStartupPolls,
+	}
+}
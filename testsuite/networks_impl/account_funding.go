@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+)
+
+const (
+	fundingTransactionDescription = "funding"
+)
+
+// Creates an account for each of the given names and funds it with a transfer from the funder, for backends without
+//  a genesis to fund accounts from
+// The transfers are all sent before any of them is waited on, and don't show up in the gas usage report
+func fundAccountsByTransfer(
+		ctx context.Context,
+		client waitableClient,
+		funder *NonceManagingTransactor,
+		accountKeyGenerator *accountKeyGenerator,
+		balanceNAvax uint64,
+		accountNames []string) ([]*FundedAccount, error) {
+	balanceWei := new(big.Int).Mul(new(big.Int).SetUint64(balanceNAvax), big.NewInt(params.GWei))
+	logrus.Infof("Funding %v C-Chain accounts with %v nAVAX each...", len(accountNames), balanceNAvax)
+
+	accounts := []*FundedAccount{}
+	keyOrigins := []string{}
+	txHashes := []common.Hash{}
+	for _, name := range accountNames {
+		privKeyEcdsa, keyOrigin, err := accountKeyGenerator.nextKey()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting a private key for account '%v'", name)
+		}
+		account := newFundedAccount(name, privKeyEcdsa)
+		tx, err := funder.transact(ctx, fundingTransactionDescription, nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return sendTransfer(opts, funder.client, account.Address, balanceWei)
+		})
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred sending the funding transaction for account '%v'", name)
+		}
+		accounts = append(accounts, account)
+		keyOrigins = append(keyOrigins, keyOrigin)
+		txHashes = append(txHashes, tx.Hash())
+	}
+
+	for i, account := range accounts {
+		receipt, err := waitForReceipt(ctx, client, txHashes[i])
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred waiting for the funding transaction for account '%v'", account.Name)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return nil, stacktrace.NewError("The funding transaction for account '%v' failed", account.Name)
+		}
+		logrus.Infof(
+			"Funded C-Chain account '%v' with address '%v' using %v key",
+			account.Name,
+			account.Address.Hex(),
+			keyOrigins[i])
+	}
+	return accounts, nil
+}
+
+// Sends a plain value transfer using the nonce, gas price, and gas limit from the opts, the same way the generated
+//  bindings send contract transactions
+func sendTransfer(opts *bind.TransactOpts, client bind.ContractTransactor, to common.Address, valueWei *big.Int) (*types.Transaction, error) {
+	gasPrice := opts.GasPrice
+	if gasPrice == nil {
+		suggestedGasPrice, err := client.SuggestGasPrice(opts.Context)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting the suggested gas price")
+		}
+		gasPrice = suggestedGasPrice
+	}
+	// A plain transfer always takes exactly this much gas, so it's set on the opts as an explicit limit, which gas
+	//  strategies don't add estimate headroom to
+	if opts.GasLimit == 0 {
+		opts.GasLimit = params.TxGas
+	}
+	tx := types.NewTransaction(opts.Nonce.Uint64(), to, valueWei, opts.GasLimit, gasPrice, nil)
+	// Same signer as the generated bindings use
+	signedTx, err := opts.Signer(types.HomesteadSigner{}, opts.From, tx)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred signing the transfer")
+	}
+	if err := client.SendTransaction(opts.Context, signedTx); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred sending the transfer")
+	}
+	return signedTx, nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"testing"
+	"time"
+)
+
+const (
+	testFundingBalanceNAvax = 5 * params.GWei
+
+	fundingTestTimeout = 30 * time.Second
+)
+
+// The external RPC backend funds its accounts the same way, from the funded key it's given
+func TestFundAccountsByTransferFundsEachAccountInOrder(t *testing.T) {
+	backend, funder := newFundingTestBackend(t, new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(params.Ether)))
+	defer backend.Close()
+	generator := newFundingTestKeyGenerator(t)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), fundingTestTimeout)
+	defer cancelFunc()
+
+	accountNames := []string{"alice", "bob", "carol"}
+	accounts, err := fundAccountsByTransfer(ctx, backend, funder, generator, testFundingBalanceNAvax, accountNames)
+	if err != nil {
+		t.Fatalf("An error occurred funding the accounts: %v", err)
+	}
+	if len(accounts) != len(accountNames) {
+		t.Fatalf("Expected %v accounts, but got %v", len(accountNames), len(accounts))
+	}
+
+	// The accounts get the generator's keys in order, starting from the first
+	expectedFirstAddress := "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	if address := accounts[0].Address.Hex(); address != expectedFirstAddress {
+		t.Errorf("Expected the first account to get the mnemonic's first address '%v', but got '%v'", expectedFirstAddress, address)
+	}
+	expectedBalanceWei := new(big.Int).Mul(new(big.Int).SetUint64(testFundingBalanceNAvax), big.NewInt(params.GWei))
+	for i, account := range accounts {
+		if account.Name != accountNames[i] {
+			t.Errorf("Expected account %v to be named '%v', but was named '%v'", i, accountNames[i], account.Name)
+		}
+		balance, err := backend.BalanceAt(ctx, account.Address, nil)
+		if err != nil {
+			t.Fatalf("An error occurred getting the balance of account '%v': %v", account.Name, err)
+		}
+		if balance.Cmp(expectedBalanceWei) != 0 {
+			t.Errorf("Expected account '%v' to hold %v wei, but it held %v", account.Name, expectedBalanceWei, balance)
+		}
+	}
+
+	// Each transfer took one of the funder's nonces, so transactions it sends afterwards don't collide with them
+	funderNonce, err := backend.NonceAt(ctx, funder.GetAddress(), nil)
+	if err != nil {
+		t.Fatalf("An error occurred getting the funder's nonce: %v", err)
+	}
+	if funderNonce != uint64(len(accountNames)) {
+		t.Errorf("Expected the funder to have sent %v transactions, but it sent %v", len(accountNames), funderNonce)
+	}
+}
+
+func TestFundAccountsByTransferFailsWhenFunderCantPay(t *testing.T) {
+	backend, funder := newFundingTestBackend(t, big.NewInt(params.GWei))
+	defer backend.Close()
+	generator := newFundingTestKeyGenerator(t)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), fundingTestTimeout)
+	defer cancelFunc()
+
+	if _, err := fundAccountsByTransfer(ctx, backend, funder, generator, testFundingBalanceNAvax, []string{"alice"}); err == nil {
+		t.Fatalf("Expected an error funding an account from a funder without enough AVAX, but got none")
+	}
+}
+
+func newFundingTestBackend(t *testing.T, funderBalanceWei *big.Int) (*autoMiningSimulatedBackend, *NonceManagingTransactor) {
+	funderKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("An error occurred generating the funder's key: %v", err)
+	}
+	alloc := core.GenesisAlloc{
+		crypto.PubkeyToAddress(funderKey.PublicKey): {Balance: funderBalanceWei},
+	}
+	backend := newAutoMiningSimulatedBackend(alloc, simulatedBlockGasLimit)
+	funder := NewNonceManagingTransactor(backend, bind.NewKeyedTransactor(funderKey), NewDefaultGasStrategy(), nil)
+	return backend, funder
+}
+
+func newFundingTestKeyGenerator(t *testing.T) *accountKeyGenerator {
+	generator, err := newAccountKeyGenerator(AccountKeySource{
+		Mnemonic:           hardhatMnemonic,
+		SeedHex:            "",
+		RootDerivationPath: "",
+	})
+	if err != nil {
+		t.Fatalf("An error occurred creating the account key generator: %v", err)
+	}
+	return generator
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/palantir/stacktrace"
+	"github.com/tyler-smith/go-bip39"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+const (
+	// Key used to derive the BIP-32 master key from a seed, as defined by the spec
+	bip32MasterKeyHmacKey = "Bitcoin seed"
+
+	// BIP-32 seeds must be between 128 and 512 bits
+	minSeedLenBytes = 16
+	maxSeedLenBytes = 64
+
+	privateKeyLenBytes = 32
+
+	// Child indexes at or above this are hardened
+	firstHardenedChildIdx uint32 = 0x80000000
+)
+
+// Where the keys of funded accounts come from; if neither a mnemonic nor a seed is given, keys are random
+type AccountKeySource struct {
+	// BIP-39 mnemonic; mutually exclusive with SeedHex
+	Mnemonic string	`json:"mnemonic"`
+
+	// Hex-encoded BIP-32 seed; mutually exclusive with Mnemonic
+	SeedHex string	`json:"seedHex"`
+
+	// Path that account indexes get appended to; defaults to m/44'/60'/0'/0, the standard Ethereum path, so that the
+	//  same mnemonic yields the same addresses as in MetaMask et al.
+	RootDerivationPath string	`json:"rootDerivationPath"`
+}
+
+func (source AccountKeySource) IsDeterministic() bool {
+	return source.Mnemonic != "" || source.SeedHex != ""
+}
+
+func (source AccountKeySource) Validate() error {
+	_, err := newAccountKeyGenerator(source)
+	return err
+}
+
+// Hands out private keys for funded accounts, deriving them deterministically (m/44'/60'/0'/0/0, m/44'/60'/0'/0/1, etc.)
+//  if the key source has a mnemonic or seed
+type accountKeyGenerator struct {
+	// Nil when keys are random
+	masterKey *extendedPrivateKey
+
+	rootPath accounts.DerivationPath
+
+	// Guards nextAccountIdx, since accounts can be funded from several goroutines at once
+	mutex sync.Mutex
+
+	nextAccountIdx uint32
+}
+
+func newAccountKeyGenerator(source AccountKeySource) (*accountKeyGenerator, error) {
+	if !source.IsDeterministic() {
+		if source.RootDerivationPath != "" {
+			return nil, stacktrace.NewError("A root derivation path was given, but no mnemonic or seed to derive from")
+		}
+		return &accountKeyGenerator{
+			masterKey:      nil,
+			rootPath:       nil,
+			nextAccountIdx: 0,
+		}, nil
+	}
+	if source.Mnemonic != "" && source.SeedHex != "" {
+		return nil, stacktrace.NewError("Only one of a mnemonic or a seed may be given for deriving account keys")
+	}
+
+	var seed []byte
+	if source.Mnemonic != "" {
+		var err error
+		seed, err = bip39.NewSeedWithErrorChecking(strings.TrimSpace(source.Mnemonic), "")
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "The account mnemonic isn't a valid BIP-39 mnemonic")
+		}
+	} else {
+		var err error
+		seed, err = hex.DecodeString(strings.TrimPrefix(source.SeedHex, hexStrIndicatorLeader))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred decoding the account seed hex")
+		}
+		if len(seed) < minSeedLenBytes || len(seed) > maxSeedLenBytes {
+			return nil, stacktrace.NewError(
+				"The account seed must be between %v and %v bytes, but was %v",
+				minSeedLenBytes,
+				maxSeedLenBytes,
+				len(seed))
+		}
+	}
+
+	rootPath := accounts.DefaultRootDerivationPath
+	if source.RootDerivationPath != "" {
+		var err error
+		rootPath, err = accounts.ParseDerivationPath(source.RootDerivationPath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred parsing root derivation path '%v'", source.RootDerivationPath)
+		}
+	}
+
+	masterKey, err := newMasterPrivateKey(seed)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred deriving the master key from the account seed")
+	}
+	return &accountKeyGenerator{
+		masterKey:      masterKey,
+		rootPath:       rootPath,
+		nextAccountIdx: 0,
+	}, nil
+}
+
+// Returns the next account key, along with a description of where it came from for logging; safe to call from several
+//  goroutines at once
+func (generator *accountKeyGenerator) nextKey() (*ecdsa.PrivateKey, string, error) {
+	if generator.masterKey == nil {
+		privKey, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, "", stacktrace.Propagate(err, "An error occurred generating a random private key")
+		}
+		return privKey, "random", nil
+	}
+
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+	path := make(accounts.DerivationPath, len(generator.rootPath), len(generator.rootPath) + 1)
+	copy(path, generator.rootPath)
+	path = append(path, generator.nextAccountIdx)
+
+	key := generator.masterKey
+	for _, childIdx := range path {
+		var err error
+		key, err = key.deriveChild(childIdx)
+		if err != nil {
+			return nil, "", stacktrace.Propagate(err, "An error occurred deriving the key at path '%v'", path)
+		}
+	}
+	privKey, err := crypto.ToECDSA(math.PaddedBigBytes(key.key, privateKeyLenBytes))
+	if err != nil {
+		return nil, "", stacktrace.Propagate(err, "An error occurred converting the key derived at path '%v' to ECDSA", path)
+	}
+	generator.nextAccountIdx++
+	return privKey, path.String(), nil
+}
+
+// ====================================================================================================
+//                                   BIP-32 private key derivation
+// ====================================================================================================
+type extendedPrivateKey struct {
+	key       *big.Int
+	chainCode []byte
+}
+
+func newMasterPrivateKey(seed []byte) (*extendedPrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte(bip32MasterKeyHmacKey))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:privateKeyLenBytes])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, stacktrace.NewError("The seed produces an invalid master key")
+	}
+	return &extendedPrivateKey{key: key, chainCode: sum[privateKeyLenBytes:]}, nil
+}
+
+func (parent *extendedPrivateKey) deriveChild(childIdx uint32) (*extendedPrivateKey, error) {
+	var data []byte
+	if childIdx >= firstHardenedChildIdx {
+		// Hardened child: 0x00 || ser256(k_par) || ser32(i)
+		data = append([]byte{0x00}, math.PaddedBigBytes(parent.key, privateKeyLenBytes)...)
+	} else {
+		// Normal child: serP(point(k_par)) || ser32(i)
+		parentPrivKey, err := crypto.ToECDSA(math.PaddedBigBytes(parent.key, privateKeyLenBytes))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred converting the parent key to ECDSA")
+		}
+		data = crypto.CompressPubkey(&parentPrivKey.PublicKey)
+	}
+	childIdxBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(childIdxBytes, childIdx)
+	data = append(data, childIdxBytes...)
+
+	mac := hmac.New(sha512.New, parent.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curveOrder := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:privateKeyLenBytes])
+	if tweak.Cmp(curveOrder) >= 0 {
+		return nil, stacktrace.NewError("Child %v produces an invalid key; the next index should be used instead", childIdx)
+	}
+	childKey := new(big.Int).Add(tweak, parent.key)
+	childKey.Mod(childKey, curveOrder)
+	if childKey.Sign() == 0 {
+		return nil, stacktrace.NewError("Child %v produces an invalid key; the next index should be used instead", childIdx)
+	}
+	return &extendedPrivateKey{key: childKey, chainCode: sum[privateKeyLenBytes:]}, nil
+}
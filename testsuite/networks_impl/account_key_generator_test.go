@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"testing"
+)
+
+const (
+	// The mnemonic that Hardhat and Foundry fund their default accounts from
+	hardhatMnemonic = "test test test test test test test test test test test junk"
+
+	// BIP-32 test vector 1's seed
+	bip32TestVector1SeedHex = "000102030405060708090a0b0c0d0e0f"
+)
+
+type bip32TestVectorStep struct {
+	childIdx uint32
+
+	expectedKeyHex       string
+	expectedChainCodeHex string
+}
+
+// From https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vectors; each step derives a child of the
+//  previous step's key, starting from the master key, so both hardened and non-hardened derivation are covered
+func TestDeriveChildMatchesBip32TestVectors(t *testing.T) {
+	testVectors := []struct {
+		name string
+
+		seedHex string
+
+		expectedMasterKeyHex       string
+		expectedMasterChainCodeHex string
+
+		steps []bip32TestVectorStep
+	}{
+		{
+			name:                       "Test vector 1",
+			seedHex:                    bip32TestVector1SeedHex,
+			expectedMasterKeyHex:       "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			expectedMasterChainCodeHex: "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+			steps: []bip32TestVectorStep{
+				{
+					childIdx:             firstHardenedChildIdx + 0,
+					expectedKeyHex:       "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+					expectedChainCodeHex: "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+				},
+				{
+					childIdx:             1,
+					expectedKeyHex:       "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+					expectedChainCodeHex: "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+				},
+				{
+					childIdx:             firstHardenedChildIdx + 2,
+					expectedKeyHex:       "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+					expectedChainCodeHex: "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f",
+				},
+				{
+					childIdx:             2,
+					expectedKeyHex:       "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+					expectedChainCodeHex: "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd",
+				},
+				{
+					childIdx:             1000000000,
+					expectedKeyHex:       "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+					expectedChainCodeHex: "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+				},
+			},
+		},
+		{
+			name:                       "Test vector 2",
+			seedHex:                    "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			expectedMasterKeyHex:       "4b03d6fc340455b363f51020ad3ecca4f0850280cf436c70c727923f6db46c3e",
+			expectedMasterChainCodeHex: "60499f801b896d83179a4374aeb7822aaeaceaa0db1f85ee3e904c4defbd9689",
+			steps: []bip32TestVectorStep{
+				{
+					childIdx:             0,
+					expectedKeyHex:       "abe74a98f6c7eabee0428f53798f0ab8aa1bd37873999041703c742f15ac7e1e",
+					expectedChainCodeHex: "f0909affaa7ee7abe5dd4e100598d4dc53cd709d5a5c2cac40e7412f232f7c9c",
+				},
+				{
+					childIdx:             firstHardenedChildIdx + 2147483647,
+					expectedKeyHex:       "877c779ad9687164e9c2f4f0f4ff0340814392330693ce95a58fe18fd52e6e93",
+					expectedChainCodeHex: "be17a268474a6bb9c61e1d720cf6215e2a88c5406c4aee7b38547f585c9a37d9",
+				},
+				{
+					childIdx:             1,
+					expectedKeyHex:       "704addf544a06e5ee4bea37098463c23613da32020d604506da8c0518e1da4b7",
+					expectedChainCodeHex: "f366f48f1ea9f2d1d3fe958c95ca84ea18e4c4ddb9366c336c927eb246fb38cb",
+				},
+				{
+					childIdx:             firstHardenedChildIdx + 2147483646,
+					expectedKeyHex:       "f1c7c871a54a804afe328b4c83a1c33b8e5ff48f5087273f04efa83b247d6a2d",
+					expectedChainCodeHex: "637807030d55d01f9a0cb3a7839515d796bd07706386a6eddf06cc29a65a0e29",
+				},
+				{
+					childIdx:             2,
+					expectedKeyHex:       "bb7d39bdb83ecf58f2fd82b6d918341cbef428661ef01ab97c28a4842125ac23",
+					expectedChainCodeHex: "9452b549be8cea3ecb7a84bec10dcfd94afe4d129ebfd3b3cb58eedf394ed271",
+				},
+			},
+		},
+		{
+			// Has keys with leading zero bytes, which must be kept when the keys are serialized
+			name:                       "Test vector 3",
+			seedHex:                    "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+			expectedMasterKeyHex:       "00ddb80b067e0d4993197fe10f2657a844a384589847602d56f0c629c81aae32",
+			expectedMasterChainCodeHex: "01d28a3e53cffa419ec122c968b3259e16b65076495494d97cae10bbfec3c36f",
+			steps: []bip32TestVectorStep{
+				{
+					childIdx:             firstHardenedChildIdx + 0,
+					expectedKeyHex:       "491f7a2eebc7b57028e0d3faa0acda02e75c33b03c48fb288c41e2ea44e1daef",
+					expectedChainCodeHex: "e5fea12a97b927fc9dc3d2cb0d1ea1cf50aa5a1fdc1f933e8906bb38df3377bd",
+				},
+			},
+		},
+	}
+
+	for _, testVector := range testVectors {
+		seed, err := hex.DecodeString(testVector.seedHex)
+		if err != nil {
+			t.Fatalf("%v: an error occurred decoding the seed: %v", testVector.name, err)
+		}
+		key, err := newMasterPrivateKey(seed)
+		if err != nil {
+			t.Fatalf("%v: an error occurred deriving the master key: %v", testVector.name, err)
+		}
+		assertExtendedKey(t, testVector.name + " master", key, testVector.expectedMasterKeyHex, testVector.expectedMasterChainCodeHex)
+
+		for i, step := range testVector.steps {
+			key, err = key.deriveChild(step.childIdx)
+			if err != nil {
+				t.Fatalf("%v step %v: an error occurred deriving child %v: %v", testVector.name, i, step.childIdx, err)
+			}
+			assertExtendedKey(t, testVector.name, key, step.expectedKeyHex, step.expectedChainCodeHex)
+		}
+	}
+}
+
+func TestNextKeyFromMnemonicMatchesStandardEthereumAddresses(t *testing.T) {
+	generator, err := newAccountKeyGenerator(AccountKeySource{
+		Mnemonic:           hardhatMnemonic,
+		SeedHex:            "",
+		RootDerivationPath: "",
+	})
+	if err != nil {
+		t.Fatalf("An error occurred creating the account key generator: %v", err)
+	}
+
+	expectedAddresses := []struct {
+		path    string
+		address string
+	}{
+		{path: "m/44'/60'/0'/0/0", address: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+		{path: "m/44'/60'/0'/0/1", address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+	}
+	for _, expected := range expectedAddresses {
+		privKey, keyOrigin, err := generator.nextKey()
+		if err != nil {
+			t.Fatalf("An error occurred getting the key at '%v': %v", expected.path, err)
+		}
+		if keyOrigin != expected.path {
+			t.Fatalf("Expected the key to be derived at '%v', but it was derived at '%v'", expected.path, keyOrigin)
+		}
+		if address := crypto.PubkeyToAddress(privKey.PublicKey).Hex(); address != expected.address {
+			t.Fatalf("Expected the key at '%v' to have address '%v', but it had '%v'", expected.path, expected.address, address)
+		}
+	}
+}
+
+// Derives under a custom root path from a hex seed, so that the third account key lands on a key from BIP-32 test
+//  vector 1 (m/0'/1/2'/2)
+func TestNextKeyFromSeedHexUsesRootDerivationPath(t *testing.T) {
+	generator, err := newAccountKeyGenerator(AccountKeySource{
+		Mnemonic:           "",
+		SeedHex:            hexStrIndicatorLeader + bip32TestVector1SeedHex,
+		RootDerivationPath: "m/0'/1/2'",
+	})
+	if err != nil {
+		t.Fatalf("An error occurred creating the account key generator: %v", err)
+	}
+
+	expectedPaths := []string{"m/0'/1/2'/0", "m/0'/1/2'/1", "m/0'/1/2'/2"}
+	var lastKeyHex string
+	for _, expectedPath := range expectedPaths {
+		privKey, keyOrigin, err := generator.nextKey()
+		if err != nil {
+			t.Fatalf("An error occurred getting the key at '%v': %v", expectedPath, err)
+		}
+		if keyOrigin != expectedPath {
+			t.Fatalf("Expected the key to be derived at '%v', but it was derived at '%v'", expectedPath, keyOrigin)
+		}
+		lastKeyHex = hex.EncodeToString(crypto.FromECDSA(privKey))
+	}
+	expectedKeyHex := "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"
+	if lastKeyHex != expectedKeyHex {
+		t.Fatalf("Expected the key at '%v' to be '%v', but was '%v'", expectedPaths[len(expectedPaths) - 1], expectedKeyHex, lastKeyHex)
+	}
+}
+
+func assertExtendedKey(t *testing.T, description string, key *extendedPrivateKey, expectedKeyHex string, expectedChainCodeHex string) {
+	if actualKeyHex := hex.EncodeToString(math.PaddedBigBytes(key.key, privateKeyLenBytes)); actualKeyHex != expectedKeyHex {
+		t.Fatalf("%v: expected key '%v', but was '%v'", description, expectedKeyHex, actualKeyHex)
+	}
+	if actualChainCodeHex := hex.EncodeToString(key.chainCode); actualChainCodeHex != expectedChainCodeHex {
+		t.Fatalf("%v: expected chain code '%v', but was '%v'", description, expectedChainCodeHex, actualChainCodeHex)
+	}
+}
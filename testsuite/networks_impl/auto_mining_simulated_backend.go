@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// Wraps go-ethereum's SimulatedBackend so that it behaves like a real node from the point of view of a transaction
+//  sender:
+//  - SimulatedBackend only mines when Commit is called, but the C-Chain produces a block as soon as it has a
+//    transaction, so every transaction gets mined in its own block as soon as it's sent
+//  - SimulatedBackend panics on transactions that can't be applied (wrong nonce, insufficient funds, etc.), whereas a
+//    node rejects them with an error; and a node holds on to transactions whose nonce is too high until the gap in
+//    front of them is filled, which NonceManagingTransactor relies on when several goroutines send at once
+type autoMiningSimulatedBackend struct {
+	*backends.SimulatedBackend
+
+	// The signer that SimulatedBackend uses to recover senders
+	signer types.Signer
+
+	blockGasLimit uint64
+
+	// Serializes sends, so that the nonce checks and the send happen atomically
+	mutex sync.Mutex
+
+	// Transactions whose nonce is ahead of their sender's next nonce, keyed by sender and then nonce
+	queuedTxs map[common.Address]map[uint64]*types.Transaction
+}
+
+func newAutoMiningSimulatedBackend(alloc core.GenesisAlloc, blockGasLimit uint64) *autoMiningSimulatedBackend {
+	return &autoMiningSimulatedBackend{
+		SimulatedBackend: backends.NewSimulatedBackend(alloc, blockGasLimit),
+		signer:           types.NewEIP155Signer(params.AllEthashProtocolChanges.ChainID),
+		blockGasLimit:    blockGasLimit,
+		queuedTxs:        map[common.Address]map[uint64]*types.Transaction{},
+	}
+}
+
+func (backend *autoMiningSimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	sender, err := types.Sender(backend.signer, tx)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred recovering the sender of transaction '%v'", tx.Hash().Hex())
+	}
+	nextNonce, err := backend.SimulatedBackend.PendingNonceAt(ctx, sender)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the next nonce for account '%v'", sender.Hex())
+	}
+	if tx.Nonce() < nextNonce {
+		return stacktrace.NewError(
+			"Nonce too low: transaction '%v' has nonce %v, but the next nonce for account '%v' is %v",
+			tx.Hash().Hex(),
+			tx.Nonce(),
+			sender.Hex(),
+			nextNonce)
+	}
+	if tx.Nonce() > nextNonce {
+		if _, found := backend.queuedTxs[sender]; !found {
+			backend.queuedTxs[sender] = map[uint64]*types.Transaction{}
+		}
+		backend.queuedTxs[sender][tx.Nonce()] = tx
+		logrus.Debugf(
+			"Queued transaction '%v' with nonce %v until account '%v' has sent nonce %v",
+			tx.Hash().Hex(),
+			tx.Nonce(),
+			sender.Hex(),
+			nextNonce)
+		return nil
+	}
+
+	if err := backend.mineTransaction(ctx, sender, tx); err != nil {
+		return stacktrace.Propagate(err, "An error occurred mining transaction '%v'", tx.Hash().Hex())
+	}
+
+	// This transaction may have filled the gap in front of queued ones
+	for {
+		nextNonce++
+		queuedTx, found := backend.queuedTxs[sender][nextNonce]
+		if !found {
+			break
+		}
+		delete(backend.queuedTxs[sender], nextNonce)
+		if err := backend.mineTransaction(ctx, sender, queuedTx); err != nil {
+			// The queued transaction's sender already got a nil error back, so, like a node dropping a transaction from
+			//  its pool, the best we can do is log it
+			logrus.Errorf("Dropped queued transaction '%v' because it couldn't be mined: %v", queuedTx.Hash().Hex(), err)
+			break
+		}
+	}
+	return nil
+}
+
+// Mines the transaction in a block of its own, after checking for the problems that would make SimulatedBackend panic
+func (backend *autoMiningSimulatedBackend) mineTransaction(ctx context.Context, sender common.Address, tx *types.Transaction) error {
+	if tx.Gas() > backend.blockGasLimit {
+		return stacktrace.NewError("Transaction gas limit %v exceeds the block gas limit %v", tx.Gas(), backend.blockGasLimit)
+	}
+	intrinsicGas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true, true)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred calculating the transaction's intrinsic gas")
+	}
+	if tx.Gas() < intrinsicGas {
+		return stacktrace.NewError("Transaction gas limit %v is below the intrinsic gas %v", tx.Gas(), intrinsicGas)
+	}
+	balance, err := backend.SimulatedBackend.BalanceAt(ctx, sender, nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the balance of account '%v'", sender.Hex())
+	}
+	if balance.Cmp(tx.Cost()) < 0 {
+		return stacktrace.NewError(
+			"Insufficient funds: account '%v' has %v wei, but gas * price + value is %v wei",
+			sender.Hex(),
+			balance,
+			tx.Cost())
+	}
+
+	if err := backend.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return stacktrace.Propagate(err, "An error occurred sending the transaction to the simulated backend")
+	}
+	backend.SimulatedBackend.Commit()
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"fmt"
+	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/kurtosis/servicesavalanche/avalanchegonode"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/palantir/stacktrace"
+	"strings"
+)
+
+const (
+	shellBinary       = "/bin/sh"
+	shellCommandFlag  = "-c"
+	numShellCmdArgs   = 3
+	shellCommandIndex = 2
+)
+
+// Launches a node with the avalanchego-kurtosis container config, but on the given ports and with node flags that it has
+//  no setting for
+type avalancheNodeContainerConfigFactory struct {
+	wrappedFactory *avalanchegonode.AvalancheGoContainerConfigFactory
+
+	ports NodePorts
+
+	additionalNodeFlags []string
+}
+
+func newAvalancheNodeContainerConfigFactory(
+		wrappedFactory *avalanchegonode.AvalancheGoContainerConfigFactory,
+		ports NodePorts,
+		additionalNodeFlags []string) *avalancheNodeContainerConfigFactory {
+	return &avalancheNodeContainerConfigFactory{
+		wrappedFactory:      wrappedFactory,
+		ports:               ports,
+		additionalNodeFlags: additionalNodeFlags,
+	}
+}
+
+func (factory avalancheNodeContainerConfigFactory) GetCreationConfig(containerIpAddr string) (*services.ContainerCreationConfig, error) {
+	wrappedCreationConfig, err := factory.wrappedFactory.GetCreationConfig(containerIpAddr)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the avalanchego-kurtosis container creation config")
+	}
+
+	// The node's service is what reports its ports to everything else, including the nodes that bootstrap from it
+	ports := factory.ports
+	serviceCreatingFunc := func(serviceCtx *services.ServiceContext) services.Service {
+		return avalanchegonode.NewNodeAPIService(serviceCtx, ports.HTTPPort, ports.StakingPort)
+	}
+	result := services.NewContainerCreationConfigBuilder(
+			wrappedCreationConfig.GetImage(),
+			wrappedCreationConfig.GetTestVolumeMountpoint(),
+			serviceCreatingFunc).
+		WithUsedPorts(map[string]bool{
+			fmt.Sprintf("%v/tcp", ports.HTTPPort):    true,
+			fmt.Sprintf("%v/tcp", ports.StakingPort): true,
+		}).
+		WithGeneratedFiles(wrappedCreationConfig.GetFileGeneratingFuncs()).
+		WithFilesArtifacts(wrappedCreationConfig.GetFilesArtifactMountpoints()).
+		Build()
+	return result, nil
+}
+
+func (factory avalancheNodeContainerConfigFactory) GetRunConfig(containerIpAddr string, generatedFileFilepaths map[string]string) (*services.ContainerRunConfig, error) {
+	wrappedRunConfig, err := factory.wrappedFactory.GetRunConfig(containerIpAddr, generatedFileFilepaths)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the avalanchego-kurtosis container run config")
+	}
+	// The avalanchego-kurtosis port flags are hardcoded, but the node takes the last value given for a flag
+	nodeFlags := append(factory.ports.getNodeFlags(), factory.additionalNodeFlags...)
+	cmdArgs := appendNodeFlags(wrappedRunConfig.GetCmdOverrideArgs(), nodeFlags)
+	result := services.NewContainerRunConfigBuilder().
+		WithEntrypointOverride(wrappedRunConfig.GetEntrypointOverrideArgs()).
+		WithCmdOverride(cmdArgs).
+		WithEnvironmentVariableOverrides(wrappedRunConfig.GetEnvironmentVariableOverrides()).
+		Build()
+	return result, nil
+}
+
+// The avalanchego-kurtosis factory runs the node through 'sh -c' when it needs to rename the node's config file first,
+//  in which case the flags have to go on the end of the shell command rather than in their own args
+func appendNodeFlags(cmdArgs []string, flags []string) []string {
+	result := append([]string{}, cmdArgs...)
+	if len(flags) == 0 {
+		return result
+	}
+	if len(result) == numShellCmdArgs && result[0] == shellBinary && result[1] == shellCommandFlag {
+		result[shellCommandIndex] = result[shellCommandIndex] + " " + strings.Join(flags, " ")
+		return result
+	}
+	return append(result, flags...)
+}
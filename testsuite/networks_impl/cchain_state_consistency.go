@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Describes which pieces of C-Chain state should be compared across nodes
+type CChainStateQuery struct {
+	// Every node must have accepted at least this block; usually the block of the transaction that was just mined
+	MinBlockNumber uint64
+
+	// Accounts whose balance and code get compared
+	Accounts []common.Address
+
+	// Contract storage slots that get compared, keyed by contract address
+	StorageSlots map[common.Address][]common.Hash
+}
+
+// Snapshot of the queried state as seen by a single node, keyed by a description of each piece of state
+type cChainStateSnapshot map[string]string
+
+// The fields of a C-Chain block that get compared, as the node reports them; the block's header can't be used for this
+//  because go-ethereum hashes it without the C-Chain's extra header fields, giving the wrong block hash
+type cChainBlockSummary struct {
+	Hash common.Hash `json:"hash"`
+
+	StateRoot common.Hash `json:"stateRoot"`
+}
+
+// Asks every node for the block hash, state root, and queried account/storage state at the highest block that all nodes
+//  have accepted, returning an error containing a per-node diff if any of them disagree
+func (network SmartContractAvalancheNetwork) AssertCChainStateConsistent(ctx context.Context, query CChainStateQuery) error {
+	nodeIds := []string{}
+	for id := range network.cChainClients {
+		nodeIds = append(nodeIds, id)
+	}
+	sort.Strings(nodeIds)
+	if len(nodeIds) == 0 {
+		return stacktrace.NewError("Can't check C-Chain state consistency because no node clients exist; has the network been set up?")
+	}
+
+	latestBlockNumbers := map[string]*big.Int{}
+	for _, id := range nodeIds {
+		latestHeader, err := network.cChainClients[id].Http.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred getting the latest accepted block header from node '%v'", id)
+		}
+		latestBlockNumbers[id] = latestHeader.Number
+	}
+	commonBlockNumber, err := getCommonBlockNumber(latestBlockNumbers, query.MinBlockNumber)
+	if err != nil {
+		return stacktrace.Propagate(err, "The nodes haven't all accepted the required block")
+	}
+
+	snapshots := map[string]cChainStateSnapshot{}
+	for _, id := range nodeIds {
+		snapshot, err := network.getCChainStateSnapshot(ctx, id, commonBlockNumber, query)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred getting the C-Chain state from node '%v' at block %v", id, commonBlockNumber)
+		}
+		snapshots[id] = snapshot
+	}
+
+	if diffs := diffCChainStateSnapshots(snapshots); len(diffs) > 0 {
+		return stacktrace.NewError(
+			"Nodes disagree on the C-Chain state at block %v:\n%v",
+			commonBlockNumber,
+			strings.Join(diffs, "\n"))
+	}
+	logrus.Infof("All %v nodes agree on the queried C-Chain state at block %v", len(nodeIds), commonBlockNumber)
+	return nil
+}
+
+func (network SmartContractAvalancheNetwork) getCChainStateSnapshot(
+		ctx context.Context,
+		nodeId string,
+		blockNumber *big.Int,
+		query CChainStateQuery) (cChainStateSnapshot, error) {
+	client := network.cChainClients[nodeId].Http
+	snapshot := cChainStateSnapshot{}
+
+	var block *cChainBlockSummary
+	if err := network.cChainClients[nodeId].HttpRpc.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(blockNumber), false); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting block %v", blockNumber)
+	}
+	if block == nil {
+		return nil, stacktrace.NewError("The node has no block %v", blockNumber)
+	}
+	snapshot["block hash"] = block.Hash.Hex()
+	snapshot["state root"] = block.StateRoot.Hex()
+
+	for _, account := range query.Accounts {
+		balance, err := client.BalanceAt(ctx, account, blockNumber)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting the balance of account '%v'", account.Hex())
+		}
+		snapshot[fmt.Sprintf("balance of %v", account.Hex())] = balance.String()
+
+		code, err := client.CodeAt(ctx, account, blockNumber)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting the code of account '%v'", account.Hex())
+		}
+		snapshot[fmt.Sprintf("code hash of %v", account.Hex())] = crypto.Keccak256Hash(code).Hex()
+	}
+
+	for contract, slots := range query.StorageSlots {
+		for _, slot := range slots {
+			value, err := client.StorageAt(ctx, contract, slot, blockNumber)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "An error occurred getting storage slot '%v' of contract '%v'", slot.Hex(), contract.Hex())
+			}
+			snapshot[fmt.Sprintf("storage slot %v of %v", slot.Hex(), contract.Hex())] = common.BytesToHash(value).Hex()
+		}
+	}
+	return snapshot, nil
+}
+
+// Nodes accept blocks at slightly different times, so state is compared at the highest block they've all accepted,
+//  which mustn't be below the minimum block
+func getCommonBlockNumber(latestBlockNumbers map[string]*big.Int, minBlockNumber uint64) (*big.Int, error) {
+	nodeIds := []string{}
+	for id := range latestBlockNumbers {
+		nodeIds = append(nodeIds, id)
+	}
+	sort.Strings(nodeIds)
+	if len(nodeIds) == 0 {
+		return nil, stacktrace.NewError("No nodes were given to find a common block for")
+	}
+
+	var commonBlockNumber *big.Int
+	for _, id := range nodeIds {
+		latestBlockNumber := latestBlockNumbers[id]
+		if latestBlockNumber.Uint64() < minBlockNumber {
+			return nil, stacktrace.NewError(
+				"Node '%v' has only accepted up to block %v, but block %v was required",
+				id,
+				latestBlockNumber,
+				minBlockNumber)
+		}
+		if commonBlockNumber == nil || latestBlockNumber.Cmp(commonBlockNumber) < 0 {
+			commonBlockNumber = latestBlockNumber
+		}
+	}
+	return commonBlockNumber, nil
+}
+
+// Returns a description of every piece of state that the nodes don't all agree on, listing each node's value, sorted by
+//  the piece of state; a piece of state missing from a node's snapshot counts as a disagreement
+func diffCChainStateSnapshots(snapshots map[string]cChainStateSnapshot) []string {
+	nodeIds := []string{}
+	stateKeySet := map[string]bool{}
+	for id, snapshot := range snapshots {
+		nodeIds = append(nodeIds, id)
+		for key := range snapshot {
+			stateKeySet[key] = true
+		}
+	}
+	sort.Strings(nodeIds)
+	stateKeys := []string{}
+	for key := range stateKeySet {
+		stateKeys = append(stateKeys, key)
+	}
+	sort.Strings(stateKeys)
+
+	diffs := []string{}
+	for _, key := range stateKeys {
+		isConsistent := true
+		expectedValue, expectedFound := snapshots[nodeIds[0]][key]
+		for _, id := range nodeIds {
+			value, found := snapshots[id][key]
+			if value != expectedValue || found != expectedFound {
+				isConsistent = false
+				break
+			}
+		}
+		if isConsistent {
+			continue
+		}
+		nodeValueStrs := []string{}
+		for _, id := range nodeIds {
+			value, found := snapshots[id][key]
+			if !found {
+				value = "<missing>"
+			}
+			nodeValueStrs = append(nodeValueStrs, fmt.Sprintf("    %v: %v", id, value))
+		}
+		diffs = append(diffs, fmt.Sprintf("%v:\n%v", key, strings.Join(nodeValueStrs, "\n")))
+	}
+	return diffs
+}
+
+// For backends with a single client, which can't disagree with itself: only checks that the client has seen the
+//  query's minimum block
+func assertSingleClientReachedBlock(ctx context.Context, client ethereum.ChainReader, query CChainStateQuery) error {
+	latestHeader, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the latest accepted block header")
+	}
+	if latestHeader.Number.Uint64() < query.MinBlockNumber {
+		return stacktrace.NewError(
+			"The client has only accepted up to block %v, but block %v was required",
+			latestHeader.Number,
+			query.MinBlockNumber)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	consistencyTestBlockHash      = common.HexToHash("0xb1")
+	consistencyTestOtherBlockHash = common.HexToHash("0xb2")
+	consistencyTestStateRoot      = common.HexToHash("0x5a")
+)
+
+func TestGetCommonBlockNumber(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		latestBlockNumbers map[string]int64
+		minBlockNumber     uint64
+
+		expectedBlockNumber int64
+		isErrExpected       bool
+	}{
+		{
+			name:                "Single node",
+			latestBlockNumbers:  map[string]int64{"a": 7},
+			minBlockNumber:      7,
+			expectedBlockNumber: 7,
+		},
+		{
+			name:                "Lowest of the nodes' latest blocks",
+			latestBlockNumbers:  map[string]int64{"a": 9, "b": 6, "c": 8},
+			minBlockNumber:      5,
+			expectedBlockNumber: 6,
+		},
+		{
+			name:                "No minimum",
+			latestBlockNumbers:  map[string]int64{"a": 0, "b": 3},
+			minBlockNumber:      0,
+			expectedBlockNumber: 0,
+		},
+		{
+			name:               "A node below the minimum",
+			latestBlockNumbers: map[string]int64{"a": 9, "b": 4},
+			minBlockNumber:     5,
+			isErrExpected:      true,
+		},
+		{
+			name:               "No nodes",
+			latestBlockNumbers: map[string]int64{},
+			isErrExpected:      true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		latestBlockNumbers := map[string]*big.Int{}
+		for id, blockNumber := range testCase.latestBlockNumbers {
+			latestBlockNumbers[id] = big.NewInt(blockNumber)
+		}
+		commonBlockNumber, err := getCommonBlockNumber(latestBlockNumbers, testCase.minBlockNumber)
+		if testCase.isErrExpected {
+			if err == nil {
+				t.Errorf("%v: expected an error, but got common block %v", testCase.name, commonBlockNumber)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: expected a common block, but got an error: %v", testCase.name, err)
+			continue
+		}
+		if commonBlockNumber.Int64() != testCase.expectedBlockNumber {
+			t.Errorf("%v: expected common block %v, but was %v", testCase.name, testCase.expectedBlockNumber, commonBlockNumber)
+		}
+	}
+}
+
+func TestDiffCChainStateSnapshots(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		snapshots map[string]cChainStateSnapshot
+
+		expectedDiffs []string
+	}{
+		{
+			name: "Agreement",
+			snapshots: map[string]cChainStateSnapshot{
+				"a": {"block hash": "0x1", "state root": "0x2"},
+				"b": {"block hash": "0x1", "state root": "0x2"},
+			},
+			expectedDiffs: []string{},
+		},
+		{
+			name: "Single node",
+			snapshots: map[string]cChainStateSnapshot{
+				"a": {"block hash": "0x1"},
+			},
+			expectedDiffs: []string{},
+		},
+		{
+			name: "Every node's value is listed, sorted by node",
+			snapshots: map[string]cChainStateSnapshot{
+				"c": {"block hash": "0x1", "state root": "0x2"},
+				"a": {"block hash": "0x1", "state root": "0x2"},
+				"b": {"block hash": "0x1", "state root": "0x3"},
+			},
+			expectedDiffs: []string{
+				"state root:\n    a: 0x2\n    b: 0x3\n    c: 0x2",
+			},
+		},
+		{
+			name: "Disagreements are sorted by state",
+			snapshots: map[string]cChainStateSnapshot{
+				"a": {"state root": "0x2", "block hash": "0x1", "balance of 0xA": "5"},
+				"b": {"state root": "0x3", "block hash": "0x4", "balance of 0xA": "5"},
+			},
+			expectedDiffs: []string{
+				"block hash:\n    a: 0x1\n    b: 0x4",
+				"state root:\n    a: 0x2\n    b: 0x3",
+			},
+		},
+		{
+			name: "State missing from the first node",
+			snapshots: map[string]cChainStateSnapshot{
+				"a": {"block hash": "0x1"},
+				"b": {"block hash": "0x1", "state root": "0x2"},
+			},
+			expectedDiffs: []string{
+				"state root:\n    a: <missing>\n    b: 0x2",
+			},
+		},
+		{
+			name: "Empty value isn't the same as missing",
+			snapshots: map[string]cChainStateSnapshot{
+				"a": {"code hash of 0xA": ""},
+				"b": {},
+			},
+			expectedDiffs: []string{
+				"code hash of 0xA:\n    a: \n    b: <missing>",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		diffs := diffCChainStateSnapshots(testCase.snapshots)
+		if !reflect.DeepEqual(diffs, testCase.expectedDiffs) {
+			t.Errorf("%v: expected diffs %q, but got %q", testCase.name, testCase.expectedDiffs, diffs)
+		}
+	}
+}
+
+// The fake nodes' block hashes aren't what go-ethereum would compute from their headers, so the hashes only agree if
+//  they're taken from what the nodes report
+func TestAssertCChainStateConsistentComparesReportedBlockHashes(t *testing.T) {
+	firstNode := newFakeCChainNode()
+	firstNode.setBlock(1, consistencyTestBlockHash, consistencyTestStateRoot)
+	firstNode.setBlock(2, common.HexToHash("0xc1"), consistencyTestStateRoot)
+	laggingNode := newFakeCChainNode()
+	laggingNode.setBlock(1, consistencyTestBlockHash, consistencyTestStateRoot)
+	network := newFakeNodesTestNetwork(t, map[string]*fakeCChainNode{"first": firstNode, "lagging": laggingNode})
+	defer network.Close()
+
+	// Block 1 is the highest block both nodes have
+	if err := network.AssertCChainStateConsistent(context.Background(), CChainStateQuery{MinBlockNumber: 1}); err != nil {
+		t.Fatalf("Expected the nodes to agree on the C-Chain state, but got an error: %v", err)
+	}
+	if err := network.AssertCChainStateConsistent(context.Background(), CChainStateQuery{MinBlockNumber: 2}); err == nil {
+		t.Fatalf("Expected an error when a node hasn't accepted the minimum block, but got none")
+	}
+}
+
+func TestAssertCChainStateConsistentReportsBlockHashDisagreement(t *testing.T) {
+	firstNode := newFakeCChainNode()
+	firstNode.setBlock(1, consistencyTestBlockHash, consistencyTestStateRoot)
+	forkedNode := newFakeCChainNode()
+	forkedNode.setBlock(1, consistencyTestOtherBlockHash, consistencyTestStateRoot)
+	network := newFakeNodesTestNetwork(t, map[string]*fakeCChainNode{"first": firstNode, "forked": forkedNode})
+	defer network.Close()
+
+	err := network.AssertCChainStateConsistent(context.Background(), CChainStateQuery{MinBlockNumber: 1})
+	if err == nil {
+		t.Fatalf("Expected the nodes to disagree on the block hash, but got no error")
+	}
+	expectedDiff := "block hash:\n    first: " + consistencyTestBlockHash.Hex() + "\n    forked: " + consistencyTestOtherBlockHash.Hex()
+	if !strings.Contains(err.Error(), expectedDiff) {
+		t.Errorf("Expected the error to contain the block hash diff %q, but got: %v", expectedDiff, err)
+	}
+	if strings.Contains(err.Error(), "state root:") {
+		t.Errorf("Expected the nodes to agree on the state root, but the error reported a diff: %v", err)
+	}
+}
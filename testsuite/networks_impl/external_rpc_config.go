@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/palantir/stacktrace"
+	"io/ioutil"
+	"strings"
+)
+
+// An existing C-Chain node to run tests against instead of an Avalanche network set up by Kurtosis, along with the key
+//  of an account on it that holds AVAX; the key comes from either a hex private key or a keystore file
+// NOTE: The URL must be reachable from inside the testsuite container, so a node on the host machine needs e.g.
+//  'host.docker.internal' rather than 'localhost'
+type ExternalRpcConfig struct {
+	// If empty, tests set up their own Avalanche network
+	RpcUrl string	`json:"rpcUrl"`
+
+	// Hex-encoded private key of the funded account; mutually exclusive with KeystoreFilepath
+	FundedPrivateKeyHex string	`json:"fundedPrivateKeyHex"`
+
+	// go-ethereum keystore (V3) file containing the funded account's key; mutually exclusive with FundedPrivateKeyHex
+	KeystoreFilepath string	`json:"keystoreFilepath"`
+
+	KeystorePassword string	`json:"keystorePassword"`
+}
+
+func (config ExternalRpcConfig) IsEnabled() bool {
+	return config.RpcUrl != ""
+}
+
+func (config ExternalRpcConfig) Validate() error {
+	if !config.IsEnabled() {
+		if config.FundedPrivateKeyHex != "" || config.KeystoreFilepath != "" {
+			return stacktrace.NewError("A funded key was given for an external RPC endpoint, but no RPC URL")
+		}
+		return nil
+	}
+	if _, err := config.LoadFundedPrivateKey(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred loading the funded private key")
+	}
+	return nil
+}
+
+// Reads the funded account's key from whichever of the hex private key or keystore file was given
+func (config ExternalRpcConfig) LoadFundedPrivateKey() (*ecdsa.PrivateKey, error) {
+	if config.FundedPrivateKeyHex != "" && config.KeystoreFilepath != "" {
+		return nil, stacktrace.NewError("Only one of a funded private key or a keystore file may be given")
+	}
+	if config.FundedPrivateKeyHex != "" {
+		privKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(config.FundedPrivateKeyHex), hexStrIndicatorLeader))
+		if err != nil {
+			// The error isn't propagated, because it can contain the key
+			return nil, stacktrace.NewError("The funded private key isn't a valid hex-encoded secp256k1 private key")
+		}
+		return privKey, nil
+	}
+	if config.KeystoreFilepath != "" {
+		keyJson, err := ioutil.ReadFile(config.KeystoreFilepath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred reading keystore file '%v'", config.KeystoreFilepath)
+		}
+		key, err := keystore.DecryptKey(keyJson, config.KeystorePassword)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred decrypting keystore file '%v'", config.KeystoreFilepath)
+		}
+		return key.PrivateKey, nil
+	}
+	return nil, stacktrace.NewError("Either a funded private key or a keystore file must be given along with the RPC URL")
+}
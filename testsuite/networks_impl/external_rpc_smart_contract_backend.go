@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+)
+
+// A SmartContractBackend for a C-Chain node that's already running (e.g. a local avalanchego), reached through its RPC
+//  endpoint
+// HTTP endpoints work, but waits poll rather than subscribing to new heads, so websocket endpoints are faster
+type ExternalRpcSmartContractBackend struct {
+	rpcUrl string
+
+	client *ethclient.Client
+
+	chainId *big.Int
+
+	// Recovers transaction senders, for revert reasons
+	signer types.Signer
+
+	fundedAccountBalance uint64
+
+	gasStrategy GasStrategy
+
+	gasUsageRecorder *GasUsageRecorder
+
+	// Only used for the accounts returned by GetFundedAccounts, since the transactor's key is given
+	accountKeyGenerator *accountKeyGenerator
+
+	// Wraps the given funded key; also funds the accounts returned by GetFundedAccounts, so that funding and test
+	//  transactions draw from the same nonces
+	nonceManagingTransactor *NonceManagingTransactor
+}
+
+// Uses the same parts of the config as NewSimulatedSmartContractBackend
+// The funded key's account pays for the transactions sent through the backend's transactor and for funding the accounts
+//  returned by GetFundedAccounts, so it must hold enough AVAX for both
+func NewExternalRpcSmartContractBackend(
+		ctx context.Context,
+		rpcUrl string,
+		fundedPrivateKey *ecdsa.PrivateKey,
+		config SmartContractAvalancheNetworkConfig) (*ExternalRpcSmartContractBackend, error) {
+	if err := config.Gas.Validate(); err != nil {
+		return nil, stacktrace.Propagate(err, "The gas strategy is invalid")
+	}
+	accountKeyGenerator, err := newAccountKeyGenerator(config.AccountKeys)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred creating the account key generator")
+	}
+
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred connecting to C-Chain RPC endpoint '%v'", rpcUrl)
+	}
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, stacktrace.Propagate(err, "An error occurred getting the chain ID from C-Chain RPC endpoint '%v'", rpcUrl)
+	}
+
+	transactorAccount := newFundedAccount(transactorAccountName, fundedPrivateKey)
+	balance, err := client.BalanceAt(ctx, transactorAccount.Address, nil)
+	if err != nil {
+		client.Close()
+		return nil, stacktrace.Propagate(err, "An error occurred getting the balance of funded account '%v'", transactorAccount.Address.Hex())
+	}
+	if balance.Sign() == 0 {
+		client.Close()
+		return nil, stacktrace.NewError("Account '%v' was given as the funded account, but it has no AVAX", transactorAccount.Address.Hex())
+	}
+	logrus.Infof(
+		"Connected to C-Chain RPC endpoint '%v' with chain ID %v, using funded address '%v' with %v wei",
+		rpcUrl,
+		chainId,
+		transactorAccount.Address.Hex(),
+		balance)
+
+	gasUsageRecorder := NewGasUsageRecorder()
+	return &ExternalRpcSmartContractBackend{
+		rpcUrl:                  rpcUrl,
+		client:                  client,
+		chainId:                 chainId,
+		signer:                  types.NewEIP155Signer(chainId),
+		fundedAccountBalance:    config.FundedAccountBalance,
+		gasStrategy:             config.Gas,
+		gasUsageRecorder:        gasUsageRecorder,
+		accountKeyGenerator:     accountKeyGenerator,
+		nonceManagingTransactor: NewNonceManagingTransactor(client, transactorAccount.Transactor, config.Gas, gasUsageRecorder),
+	}, nil
+}
+
+func (external *ExternalRpcSmartContractBackend) GetClient() CChainClient {
+	return external.client
+}
+
+func (external *ExternalRpcSmartContractBackend) GetChainId(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(external.chainId), nil
+}
+
+func (external *ExternalRpcSmartContractBackend) GetNonceManagingTransactor() *NonceManagingTransactor {
+	return external.nonceManagingTransactor
+}
+
+// Creates an account for each of the given names and funds it from the funded account with the configured balance
+func (external *ExternalRpcSmartContractBackend) GetFundedAccounts(ctx context.Context, accountNames ...string) ([]*FundedAccount, error) {
+	accounts, err := fundAccountsByTransfer(
+		ctx,
+		external.client,
+		external.nonceManagingTransactor,
+		external.accountKeyGenerator,
+		external.fundedAccountBalance,
+		accountNames)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred funding accounts %v from the funded account", accountNames)
+	}
+	return accounts, nil
+}
+
+// Only one should be created per account, else their nonces will collide
+func (external *ExternalRpcSmartContractBackend) NewAccountTransactor(account *FundedAccount) (*NonceManagingTransactor, error) {
+	return NewNonceManagingTransactor(external.client, account.Transactor, external.gasStrategy, external.gasUsageRecorder), nil
+}
+
+// There's only one node, so this only waits for the receipt; as on the Avalanche network, reverted transactions return
+//  a *TransactionRevertedError and gas usage gets recorded
+func (external *ExternalRpcSmartContractBackend) WaitForTransactionAccepted(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := waitForReceipt(ctx, external.client, txHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred waiting for transaction '%v' to be included in a block", txHash.Hex())
+	}
+	logrus.Debugf("Transaction '%v' included in block %v", txHash.Hex(), receipt.BlockNumber)
+	external.gasUsageRecorder.recordReceipt(receipt)
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		// The transaction is replayed against the end state of the previous block, which is the state it executed on top of
+		replayBlockNumber := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+		return receipt, getTransactionRevertedError(ctx, external.client, external.signer, receipt, replayBlockNumber)
+	}
+	return receipt, nil
+}
+
+func (external *ExternalRpcSmartContractBackend) WaitForBlockNumber(ctx context.Context, blockNumber uint64) error {
+	if err := waitForBlockNumber(ctx, external.client, blockNumber); err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for '%v' to accept block %v", external.rpcUrl, blockNumber)
+	}
+	return nil
+}
+
+// There's only one node, so this only checks that it has accepted the required block
+func (external *ExternalRpcSmartContractBackend) AssertCChainStateConsistent(ctx context.Context, query CChainStateQuery) error {
+	if err := assertSingleClientReachedBlock(ctx, external.client, query); err != nil {
+		return stacktrace.Propagate(err, "RPC endpoint '%v' hasn't accepted the queried block", external.rpcUrl)
+	}
+	return nil
+}
+
+func (external *ExternalRpcSmartContractBackend) GetGasUsageRecorder() *GasUsageRecorder {
+	return external.gasUsageRecorder
+}
+
+// Closes the connection to the RPC endpoint
+func (external *ExternalRpcSmartContractBackend) Close() error {
+	external.client.Close()
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	fakeNewHeadInterval = 10 * time.Millisecond
+)
+
+// Serves the parts of a node's C-Chain RPC API that the network's helpers use, over an in-process connection
+// Unlike SimulatedBackend, the block hashes it reports are made up rather than computed from go-ethereum's header type,
+//  the same way that a real C-Chain's block hashes don't match what go-ethereum computes from their headers
+type fakeCChainNode struct {
+	mutex *sync.Mutex
+
+	receipts map[common.Hash]*types.Receipt
+
+	// Keyed by block number
+	blocks map[uint64]fakeBlock
+}
+
+type fakeBlock struct {
+	// Made up, rather than the hash of the block's header
+	hash common.Hash
+
+	stateRoot common.Hash
+}
+
+func newFakeCChainNode() *fakeCChainNode {
+	return &fakeCChainNode{
+		mutex:    &sync.Mutex{},
+		receipts: map[common.Hash]*types.Receipt{},
+		blocks:   map[uint64]fakeBlock{},
+	}
+}
+
+func (node *fakeCChainNode) setReceipt(receipt *types.Receipt) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	node.receipts[receipt.TxHash] = receipt
+}
+
+func (node *fakeCChainNode) setBlock(number uint64, hash common.Hash, stateRoot common.Hash) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	node.blocks[number] = fakeBlock{hash: hash, stateRoot: stateRoot}
+}
+
+// Returns clients connected to the node, all sharing a single connection that closing any of them closes
+func (node *fakeCChainNode) dial(t *testing.T) *NodeCChainClients {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &fakeCChainNodeEthApi{node: node}); err != nil {
+		t.Fatalf("An error occurred registering the fake node's eth API: %v", err)
+	}
+	rpcClient := rpc.DialInProc(server)
+	client := ethclient.NewClient(rpcClient)
+	return &NodeCChainClients{
+		Websocket: client,
+		Http:      client,
+		HttpRpc:   rpcClient,
+	}
+}
+
+// The node's 'eth' RPC namespace
+type fakeCChainNodeEthApi struct {
+	node *fakeCChainNode
+}
+
+// Returns null for transactions the node hasn't accepted, as a real node does
+func (api *fakeCChainNodeEthApi) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	api.node.mutex.Lock()
+	defer api.node.mutex.Unlock()
+	return api.node.receipts[txHash], nil
+}
+
+// Returns the block's header fields, along with the block's made-up hash as a real node would
+func (api *fakeCChainNodeEthApi) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTxs bool) (map[string]interface{}, error) {
+	api.node.mutex.Lock()
+	defer api.node.mutex.Unlock()
+	if number == rpc.LatestBlockNumber {
+		number = 0
+		for blockNumber := range api.node.blocks {
+			if blockNumber > uint64(number) {
+				number = rpc.BlockNumber(blockNumber)
+			}
+		}
+	}
+	block, found := api.node.blocks[uint64(number)]
+	if !found {
+		return nil, nil
+	}
+	header := &types.Header{
+		Difficulty: big.NewInt(1),
+		Number:     big.NewInt(number.Int64()),
+		Root:       block.stateRoot,
+	}
+	headerJson, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(headerJson, &fields); err != nil {
+		return nil, err
+	}
+	fields["hash"] = block.hash
+	return fields, nil
+}
+
+// Sends a head at a fixed interval, so that waiters keep rechecking their conditions
+func (api *fakeCChainNodeEthApi) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	subscription := notifier.CreateSubscription()
+	go func() {
+		ticker := time.NewTicker(fakeNewHeadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				head := &types.Header{Difficulty: big.NewInt(1), Number: big.NewInt(1)}
+				if err := notifier.Notify(subscription.ID, head); err != nil {
+					return
+				}
+			case <-subscription.Err():
+				return
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+func newFakeReceipt(txHash common.Hash, blockHash common.Hash, blockNumber int64) *types.Receipt {
+	return &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		Logs:              []*types.Log{},
+		TxHash:            txHash,
+		GasUsed:           21000,
+		BlockHash:         blockHash,
+		BlockNumber:       big.NewInt(blockNumber),
+		TransactionIndex:  0,
+	}
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// A C-Chain account that has been funded with AVAX and is ready to send transactions
+type FundedAccount struct {
+	// Human-readable role of the account in the test (e.g. "owner", "attacker")
+	Name string
+
+	Address common.Address
+	PrivateKey *ecdsa.PrivateKey
+	Transactor *bind.TransactOpts
+}
+
+func newFundedAccount(name string, privateKey *ecdsa.PrivateKey) *FundedAccount {
+	return &FundedAccount{
+		Name:       name,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+		Transactor: bind.NewKeyedTransactor(privateKey),
+	}
+}
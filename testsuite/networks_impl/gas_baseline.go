@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"strings"
+)
+
+// Expected gas usage per contract method, which test gas usage gets checked against so that gas cost regressions fail
+//  the test
+type GasBaseline struct {
+	// How far above its baseline a method's gas usage may go, for methods that don't set their own tolerance
+	DefaultTolerancePercent float64 `json:"defaultTolerancePercent"`
+
+	Methods []GasBaselineEntry `json:"methods"`
+}
+
+type GasBaselineEntry struct {
+	Contract string `json:"contract"`
+	Method   string `json:"method"`
+	GasUsed  uint64 `json:"gasUsed"`
+
+	// Overrides the baseline's default tolerance if set
+	TolerancePercent *float64 `json:"tolerancePercent"`
+}
+
+func LoadGasBaseline(filepath string) (*GasBaseline, error) {
+	baselineBytes, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred reading gas baseline file '%v'", filepath)
+	}
+	baseline := &GasBaseline{}
+	if err := json.Unmarshal(baselineBytes, baseline); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred deserializing gas baseline file '%v'", filepath)
+	}
+	if err := baseline.validate(); err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid gas baseline file '%v'", filepath)
+	}
+	return baseline, nil
+}
+
+func (baseline GasBaseline) validate() error {
+	if baseline.DefaultTolerancePercent < 0 {
+		return stacktrace.NewError("The default tolerance must be >= 0%%, but was %v%%", baseline.DefaultTolerancePercent)
+	}
+	seenLabels := map[transactionLabel]bool{}
+	for _, entry := range baseline.Methods {
+		label := transactionLabel{contract: entry.Contract, method: entry.Method}
+		if seenLabels[label] {
+			return stacktrace.NewError("Method '%v.%v' has more than one baseline entry", entry.Contract, entry.Method)
+		}
+		seenLabels[label] = true
+		if entry.TolerancePercent != nil && *entry.TolerancePercent < 0 {
+			return stacktrace.NewError(
+				"The tolerance for method '%v.%v' must be >= 0%%, but was %v%%",
+				entry.Contract,
+				entry.Method,
+				*entry.TolerancePercent)
+		}
+	}
+	return nil
+}
+
+// Returns an error listing every method whose maximum gas usage in the report exceeds its baseline plus tolerance
+// Methods that aren't in the baseline only get a warning, so that adding a new method doesn't break the test
+func (baseline GasBaseline) Check(report GasUsageReport) error {
+	entriesByLabel := map[transactionLabel]GasBaselineEntry{}
+	for _, entry := range baseline.Methods {
+		entriesByLabel[transactionLabel{contract: entry.Contract, method: entry.Method}] = entry
+	}
+
+	regressions := []string{}
+	for _, usage := range report.Methods {
+		entry, found := entriesByLabel[transactionLabel{contract: usage.Contract, method: usage.Method}]
+		if !found {
+			logrus.Warnf(
+				"Method '%v.%v' used up to %v gas but has no gas baseline entry, so it wasn't checked for regressions",
+				usage.Contract,
+				usage.Method,
+				usage.MaxGasUsed)
+			continue
+		}
+		tolerancePercent := baseline.DefaultTolerancePercent
+		if entry.TolerancePercent != nil {
+			tolerancePercent = *entry.TolerancePercent
+		}
+		maxAllowedGas := uint64(float64(entry.GasUsed) * (1 + tolerancePercent / float64(percentDenominator)))
+		if usage.MaxGasUsed > maxAllowedGas {
+			regressions = append(regressions, fmt.Sprintf(
+				"%v.%v used %v gas, more than its baseline of %v + %v%% = %v",
+				usage.Contract,
+				usage.Method,
+				usage.MaxGasUsed,
+				entry.GasUsed,
+				tolerancePercent,
+				maxAllowedGas))
+		} else if usage.MaxGasUsed < entry.GasUsed {
+			logrus.Infof(
+				"Method '%v.%v' used %v gas, less than its baseline of %v; consider lowering the baseline",
+				usage.Contract,
+				usage.Method,
+				usage.MaxGasUsed,
+				entry.GasUsed)
+		}
+	}
+	if len(regressions) > 0 {
+		return stacktrace.NewError(
+			"%v methods used more gas than their baseline allows:\n%v",
+			len(regressions),
+			strings.Join(regressions, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/palantir/stacktrace"
+	"math/big"
+)
+
+const (
+	// Let the generated bindings pick the value (the node's suggested gas price, or the exact gas estimate)
+	DefaultGasMode = "default"
+
+	// Use the fixed value from the strategy
+	FixedGasMode = "fixed"
+
+	// Gas price only: the node's suggested gas price times the strategy's multiplier
+	SuggestedGasPriceMode = "suggested"
+
+	// Gas limit only: the node's gas estimate plus the strategy's headroom
+	EstimatedGasLimitMode = "estimated"
+
+	percentDenominator = 100
+)
+
+// How the gas price and gas limit of each transaction sent through a NonceManagingTransactor are chosen
+// Empty modes are treated as DefaultGasMode
+type GasStrategy struct {
+	// One of DefaultGasMode, FixedGasMode, or SuggestedGasPriceMode
+	PriceMode string `json:"priceMode"`
+
+	// Used with FixedGasMode; on the C-Chain, 1 nAVAX is 1 gwei
+	FixedPriceNAvax uint64 `json:"fixedPriceNAvax"`
+
+	// Used with SuggestedGasPriceMode; e.g. 1.5 pays 50% more than the node suggests
+	SuggestedPriceMultiplier float64 `json:"suggestedPriceMultiplier"`
+
+	// One of DefaultGasMode, FixedGasMode, or EstimatedGasLimitMode
+	LimitMode string `json:"limitMode"`
+
+	// Used with FixedGasMode
+	FixedLimit uint64 `json:"fixedLimit"`
+
+	// Used with EstimatedGasLimitMode; e.g. 20 allows 20% more gas than the estimate
+	EstimatedLimitHeadroomPercent uint64 `json:"estimatedLimitHeadroomPercent"`
+}
+
+func NewDefaultGasStrategy() GasStrategy {
+	return GasStrategy{
+		PriceMode:                     DefaultGasMode,
+		FixedPriceNAvax:               0,
+		SuggestedPriceMultiplier:      0,
+		LimitMode:                     DefaultGasMode,
+		FixedLimit:                    0,
+		EstimatedLimitHeadroomPercent: 0,
+	}
+}
+
+func (strategy GasStrategy) Validate() error {
+	switch strategy.PriceMode {
+	case "", DefaultGasMode:
+	case FixedGasMode:
+		if strategy.FixedPriceNAvax == 0 {
+			return stacktrace.NewError("A fixed gas price must be > 0 nAVAX")
+		}
+	case SuggestedGasPriceMode:
+		if strategy.SuggestedPriceMultiplier <= 0 {
+			return stacktrace.NewError("The suggested gas price multiplier must be > 0, but was %v", strategy.SuggestedPriceMultiplier)
+		}
+	default:
+		return stacktrace.NewError(
+			"Unrecognized gas price mode '%v'; must be one of '%v', '%v', or '%v'",
+			strategy.PriceMode,
+			DefaultGasMode,
+			FixedGasMode,
+			SuggestedGasPriceMode)
+	}
+
+	switch strategy.LimitMode {
+	case "", DefaultGasMode, EstimatedGasLimitMode:
+	case FixedGasMode:
+		if strategy.FixedLimit == 0 {
+			return stacktrace.NewError("A fixed gas limit must be > 0")
+		}
+	default:
+		return stacktrace.NewError(
+			"Unrecognized gas limit mode '%v'; must be one of '%v', '%v', or '%v'",
+			strategy.LimitMode,
+			DefaultGasMode,
+			FixedGasMode,
+			EstimatedGasLimitMode)
+	}
+	return nil
+}
+
+// Human-readable summary, for logging next to the transactions that used the strategy
+func (strategy GasStrategy) String() string {
+	var priceStr string
+	switch strategy.PriceMode {
+	case FixedGasMode:
+		priceStr = fmt.Sprintf("fixed at %v nAVAX", strategy.FixedPriceNAvax)
+	case SuggestedGasPriceMode:
+		priceStr = fmt.Sprintf("suggested x %v", strategy.SuggestedPriceMultiplier)
+	default:
+		priceStr = DefaultGasMode
+	}
+	var limitStr string
+	switch strategy.LimitMode {
+	case FixedGasMode:
+		limitStr = fmt.Sprintf("fixed at %v", strategy.FixedLimit)
+	case EstimatedGasLimitMode:
+		limitStr = fmt.Sprintf("estimate + %v%%", strategy.EstimatedLimitHeadroomPercent)
+	default:
+		limitStr = DefaultGasMode
+	}
+	return fmt.Sprintf("price %v, limit %v", priceStr, limitStr)
+}
+
+// Sets the gas price and limit on the transact opts according to the strategy
+// The bindings only estimate gas when the limit is 0 and have no hook for adjusting the estimate, so for
+//  EstimatedGasLimitMode the limit is left at 0 and the signer is wrapped to add the headroom to the estimated transaction
+//  before signing it
+// A limit that the caller sets on the opts after this (e.g. a scenario step's gas limit) is an explicit limit rather than
+//  an estimate, so it's signed as is
+func (strategy GasStrategy) apply(ctx context.Context, client bind.ContractTransactor, opts *bind.TransactOpts) error {
+	switch strategy.PriceMode {
+	case FixedGasMode:
+		opts.GasPrice = new(big.Int).Mul(new(big.Int).SetUint64(strategy.FixedPriceNAvax), big.NewInt(params.GWei))
+	case SuggestedGasPriceMode:
+		suggestedPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred getting the suggested gas price from the node")
+		}
+		multipliedPrice, _ := new(big.Float).Mul(
+			new(big.Float).SetInt(suggestedPrice),
+			big.NewFloat(strategy.SuggestedPriceMultiplier),
+		).Int(nil)
+		opts.GasPrice = multipliedPrice
+	}
+
+	switch strategy.LimitMode {
+	case FixedGasMode:
+		opts.GasLimit = strategy.FixedLimit
+	case EstimatedGasLimitMode:
+		opts.GasLimit = 0
+		headroomPercent := strategy.EstimatedLimitHeadroomPercent
+		wrappedSigner := opts.Signer
+		opts.Signer = func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if opts.GasLimit != 0 {
+				return wrappedSigner(signer, address, tx)
+			}
+			gasLimit := tx.Gas() * (percentDenominator + headroomPercent) / percentDenominator
+			var adjustedTx *types.Transaction
+			if tx.To() == nil {
+				adjustedTx = types.NewContractCreation(tx.Nonce(), tx.Value(), gasLimit, tx.GasPrice(), tx.Data())
+			} else {
+				adjustedTx = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), gasLimit, tx.GasPrice(), tx.Data())
+			}
+			return wrappedSigner(signer, address, adjustedTx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"testing"
+)
+
+const (
+	testHeadroomPercent = 20
+)
+
+var (
+	gasTestRecipient = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+func TestApplyEstimatedGasLimitOnlyAddsHeadroomToEstimates(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		// What the caller sets on the opts after the strategy is applied, as a scenario step's gas limit does
+		callerGasLimit uint64
+		isCreation     bool
+		txGasLimit     uint64
+
+		expectedGasLimit uint64
+	}{
+		{
+			name:             "Estimated call gets headroom",
+			callerGasLimit:   0,
+			txGasLimit:       100000,
+			expectedGasLimit: 120000,
+		},
+		{
+			name:             "Estimated deployment gets headroom",
+			callerGasLimit:   0,
+			isCreation:       true,
+			txGasLimit:       100000,
+			expectedGasLimit: 120000,
+		},
+		{
+			name:             "Explicit limit is left as is",
+			callerGasLimit:   50000,
+			txGasLimit:       50000,
+			expectedGasLimit: 50000,
+		},
+		{
+			name:             "Explicit deployment limit is left as is",
+			callerGasLimit:   1000000,
+			isCreation:       true,
+			txGasLimit:       1000000,
+			expectedGasLimit: 1000000,
+		},
+	}
+
+	strategy := newEstimatedGasTestStrategy()
+	for _, testCase := range testCases {
+		var signedTx *types.Transaction
+		opts := &bind.TransactOpts{
+			From: gasTestRecipient,
+			Signer: func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				signedTx = tx
+				return tx, nil
+			},
+		}
+		if err := strategy.apply(context.Background(), nil, opts); err != nil {
+			t.Fatalf("%v: an error occurred applying the gas strategy: %v", testCase.name, err)
+		}
+		if opts.GasLimit != 0 {
+			t.Fatalf("%v: expected the strategy to leave the limit at 0 so that the bindings estimate it, but it was %v", testCase.name, opts.GasLimit)
+		}
+		opts.GasLimit = testCase.callerGasLimit
+
+		var tx *types.Transaction
+		if testCase.isCreation {
+			tx = types.NewContractCreation(0, big.NewInt(0), testCase.txGasLimit, big.NewInt(1), nil)
+		} else {
+			tx = types.NewTransaction(0, gasTestRecipient, big.NewInt(0), testCase.txGasLimit, big.NewInt(1), nil)
+		}
+		if _, err := opts.Signer(types.HomesteadSigner{}, opts.From, tx); err != nil {
+			t.Fatalf("%v: an error occurred signing the transaction: %v", testCase.name, err)
+		}
+		if signedTx.Gas() != testCase.expectedGasLimit {
+			t.Errorf("%v: expected gas limit %v, but was %v", testCase.name, testCase.expectedGasLimit, signedTx.Gas())
+		}
+		if isCreation := signedTx.To() == nil; isCreation != testCase.isCreation {
+			t.Errorf("%v: expected the transaction to be a contract creation to be %v, but was %v", testCase.name, testCase.isCreation, isCreation)
+		}
+	}
+}
+
+// A transfer's gas is exact rather than estimated, so it gets no headroom
+func TestSendTransferKeepsExactGasUnderEstimatedGasLimits(t *testing.T) {
+	senderKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("An error occurred generating the sender's key: %v", err)
+	}
+	alloc := core.GenesisAlloc{
+		crypto.PubkeyToAddress(senderKey.PublicKey): {Balance: big.NewInt(params.Ether)},
+	}
+	backend := newAutoMiningSimulatedBackend(alloc, simulatedBlockGasLimit)
+	defer backend.Close()
+	sender := NewNonceManagingTransactor(backend, bind.NewKeyedTransactor(senderKey), newEstimatedGasTestStrategy(), nil)
+
+	tx, err := sender.transact(context.Background(), "test transfer", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return sendTransfer(opts, backend, gasTestRecipient, big.NewInt(params.GWei))
+	})
+	if err != nil {
+		t.Fatalf("An error occurred sending the transfer: %v", err)
+	}
+	if tx.Gas() != params.TxGas {
+		t.Fatalf("Expected the transfer's gas limit to be %v, but was %v", params.TxGas, tx.Gas())
+	}
+}
+
+func newEstimatedGasTestStrategy() GasStrategy {
+	return GasStrategy{
+		PriceMode:                     DefaultGasMode,
+		FixedPriceNAvax:               0,
+		SuggestedPriceMultiplier:      0,
+		LimitMode:                     EstimatedGasLimitMode,
+		FixedLimit:                    0,
+		EstimatedLimitHeadroomPercent: testHeadroomPercent,
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"sync"
+)
+
+// Gas used by a single successful transaction
+type GasUsageRecord struct {
+	Contract string `json:"contract"`
+	Method   string `json:"method"`
+	TxHash   string `json:"txHash"`
+	GasUsed  uint64 `json:"gasUsed"`
+}
+
+// Which contract method a sent transaction called
+type transactionLabel struct {
+	contract string
+	method   string
+}
+
+// Collects the gas used by every transaction sent through a NonceManagingTransactor
+// Transactions are labelled when they're sent and recorded once WaitForTransactionAccepted sees their receipt, so
+//  transactions that are never waited on, or that revert, don't show up
+type GasUsageRecorder struct {
+	mutex sync.Mutex
+
+	// Sent transactions whose receipts haven't been seen yet
+	pendingLabels map[common.Hash]transactionLabel
+
+	// In the order the receipts were seen
+	records []GasUsageRecord
+}
+
+func NewGasUsageRecorder() *GasUsageRecorder {
+	return &GasUsageRecorder{
+		pendingLabels: map[common.Hash]transactionLabel{},
+		records:       []GasUsageRecord{},
+	}
+}
+
+// Returns a copy of everything recorded so far
+func (recorder *GasUsageRecorder) GetRecords() []GasUsageRecord {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	result := make([]GasUsageRecord, len(recorder.records))
+	copy(result, recorder.records)
+	return result
+}
+
+func (recorder *GasUsageRecorder) labelSentTransaction(txHash common.Hash, contract string, method string) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.pendingLabels[txHash] = transactionLabel{contract: contract, method: method}
+}
+
+// Does nothing if the transaction wasn't labelled, or its receipt has already been recorded
+func (recorder *GasUsageRecorder) recordReceipt(receipt *types.Receipt) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	label, found := recorder.pendingLabels[receipt.TxHash]
+	if !found {
+		return
+	}
+	delete(recorder.pendingLabels, receipt.TxHash)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return
+	}
+	recorder.records = append(recorder.records, GasUsageRecord{
+		Contract: label.contract,
+		Method:   label.method,
+		TxHash:   receipt.TxHash.Hex(),
+		GasUsed:  receipt.GasUsed,
+	})
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"text/tabwriter"
+)
+
+const (
+	gasReportJsonFilenameSuffix  = "-gas-report.json"
+	gasReportTableFilenameSuffix = "-gas-report.txt"
+
+	gasReportDirPerms  = 0755
+	gasReportFilePerms = 0644
+)
+
+// Where a test's gas usage report gets written, and what it gets checked against
+type GasReportConfig struct {
+	// Directory that the JSON and table reports get written to; if empty, the table is only logged
+	ReportDirpath string
+
+	// If nil, gas usage isn't checked for regressions
+	Baseline *GasBaseline
+}
+
+// Gas used by every transaction that called the same contract method
+type MethodGasUsage struct {
+	Contract        string `json:"contract"`
+	Method          string `json:"method"`
+	NumTransactions int    `json:"numTransactions"`
+	MinGasUsed      uint64 `json:"minGasUsed"`
+	MaxGasUsed      uint64 `json:"maxGasUsed"`
+	MeanGasUsed     uint64 `json:"meanGasUsed"`
+}
+
+type GasUsageReport struct {
+	// Sorted by contract, then method
+	Methods []MethodGasUsage `json:"methods"`
+
+	Transactions []GasUsageRecord `json:"transactions"`
+}
+
+func NewGasUsageReport(records []GasUsageRecord) GasUsageReport {
+	methodsByLabel := map[transactionLabel]*MethodGasUsage{}
+	totalGasByLabel := map[transactionLabel]uint64{}
+	for _, record := range records {
+		label := transactionLabel{contract: record.Contract, method: record.Method}
+		usage, found := methodsByLabel[label]
+		if !found {
+			usage = &MethodGasUsage{
+				Contract:   record.Contract,
+				Method:     record.Method,
+				MinGasUsed: record.GasUsed,
+				MaxGasUsed: record.GasUsed,
+			}
+			methodsByLabel[label] = usage
+		}
+		usage.NumTransactions++
+		if record.GasUsed < usage.MinGasUsed {
+			usage.MinGasUsed = record.GasUsed
+		}
+		if record.GasUsed > usage.MaxGasUsed {
+			usage.MaxGasUsed = record.GasUsed
+		}
+		totalGasByLabel[label] += record.GasUsed
+	}
+
+	methods := []MethodGasUsage{}
+	for label, usage := range methodsByLabel {
+		usage.MeanGasUsed = totalGasByLabel[label] / uint64(usage.NumTransactions)
+		methods = append(methods, *usage)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if methods[i].Contract != methods[j].Contract {
+			return methods[i].Contract < methods[j].Contract
+		}
+		return methods[i].Method < methods[j].Method
+	})
+	return GasUsageReport{
+		Methods:      methods,
+		Transactions: records,
+	}
+}
+
+// Renders the per-method gas usage as a human-readable table
+func (report GasUsageReport) GetTable() string {
+	buffer := &bytes.Buffer{}
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "CONTRACT\tMETHOD\tTXNS\tMIN GAS\tMAX GAS\tMEAN GAS\t")
+	for _, usage := range report.Methods {
+		fmt.Fprintf(
+			writer,
+			"%v\t%v\t%v\t%v\t%v\t%v\t\n",
+			usage.Contract,
+			usage.Method,
+			usage.NumTransactions,
+			usage.MinGasUsed,
+			usage.MaxGasUsed,
+			usage.MeanGasUsed)
+	}
+	writer.Flush()
+	return buffer.String()
+}
+
+// Logs the report, writes it to the configured directory as both JSON and a table, and checks it against the configured
+//  baseline, returning an error describing every regression
+func (config GasReportConfig) FinishReport(reportName string, recorder *GasUsageRecorder) error {
+	report := NewGasUsageReport(recorder.GetRecords())
+	table := report.GetTable()
+	logrus.Infof("Gas usage for '%v':\n%v", reportName, table)
+
+	if config.ReportDirpath != "" {
+		if err := os.MkdirAll(config.ReportDirpath, gasReportDirPerms); err != nil {
+			return stacktrace.Propagate(err, "An error occurred creating gas report directory '%v'", config.ReportDirpath)
+		}
+		reportJsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred serializing the gas usage report to JSON")
+		}
+		jsonFilepath := path.Join(config.ReportDirpath, reportName + gasReportJsonFilenameSuffix)
+		if err := ioutil.WriteFile(jsonFilepath, reportJsonBytes, gasReportFilePerms); err != nil {
+			return stacktrace.Propagate(err, "An error occurred writing the JSON gas usage report to '%v'", jsonFilepath)
+		}
+		tableFilepath := path.Join(config.ReportDirpath, reportName + gasReportTableFilenameSuffix)
+		if err := ioutil.WriteFile(tableFilepath, []byte(table), gasReportFilePerms); err != nil {
+			return stacktrace.Propagate(err, "An error occurred writing the gas usage table to '%v'", tableFilepath)
+		}
+		logrus.Infof("Wrote gas usage reports '%v' and '%v'", jsonFilepath, tableFilepath)
+	}
+
+	if config.Baseline != nil {
+		if err := config.Baseline.Check(report); err != nil {
+			return stacktrace.Propagate(err, "Gas usage for '%v' regressed against the baseline", reportName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"fmt"
+	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/avalanche/libs/avalanchegoclient"
+	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/avalanche/libs/builder/chainhelper"
+	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/avalanche/libs/constants"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// Moves AVAX from the local network's genesis allocation to C-Chain addresses
+// NOTE: This does the same as the avalanchego-kurtosis topology.Genesis, but returns errors where that panics, so that a
+//  failed funding fails the test instead of crashing the testsuite
+type genesisFunder struct {
+	client *avalanchegoclient.Client
+
+	userPass api.UserPass
+
+	// The genesis allocation's X-Chain address, which the funds are exported from
+	xChainAddress string
+}
+
+// Creates a keystore user on the node that the client talks to, and imports the genesis allocation's key into it
+func newGenesisFunder(client *avalanchegoclient.Client, username string, password string) (*genesisFunder, error) {
+	userPass := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+	if _, err := client.KeystoreAPI().CreateUser(userPass); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred creating the genesis keystore user")
+	}
+	genesisPrivateKey := constants.DefaultLocalNetGenesisConfig.FundedAddresses.PrivateKey
+	xChainAddress, err := client.XChainAPI().ImportKey(userPass, genesisPrivateKey)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred importing the genesis key to the X-Chain")
+	}
+	if _, err := client.CChainAPI().ImportKey(userPass, genesisPrivateKey); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred importing the genesis key to the C-Chain")
+	}
+	logrus.Infof("Genesis X-Chain address: %v", xChainAddress)
+	return &genesisFunder{
+		client:        client,
+		userPass:      userPass,
+		xChainAddress: xChainAddress,
+	}, nil
+}
+
+// Funds the address with half of the genesis allocation's remaining X-Chain balance
+func (funder genesisFunder) moveHalfOfBalanceToCChain(address common.Address, txFee uint64) error {
+	balanceReply, err := funder.client.XChainAPI().GetBalance(funder.xChainAddress, constants.AvaxAssetID.String(), true)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the balance of the genesis X-Chain address")
+	}
+	balance := uint64(balanceReply.Balance)
+	if balance <= txFee {
+		return stacktrace.NewError("The genesis X-Chain balance of %v nAVAX can't cover the tx fee of %v nAVAX", balance, txFee)
+	}
+	amount := (balance - txFee) / 2
+	if err := funder.fundCChainAddresses([]common.Address{address}, amount); err != nil {
+		return stacktrace.Propagate(err, "An error occurred funding C-Chain address '%v' with %v nAVAX", address.Hex(), amount)
+	}
+	return nil
+}
+
+// Funds each address with the given amount, one at a time since every transfer spends from the same genesis account
+func (funder genesisFunder) fundCChainAddresses(addresses []common.Address, amount uint64) error {
+	// The funds are exported to the genesis key's own C-Chain address, then imported from there to the destination
+	cChainBech32Address := fmt.Sprintf("C%s", funder.xChainAddress[1:])
+	for _, address := range addresses {
+		exportTxId, err := funder.client.XChainAPI().ExportAVAX(funder.userPass, nil, "", amount, cChainBech32Address)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred exporting %v nAVAX from the X-Chain for address '%v'", amount, address.Hex())
+		}
+		if err := chainhelper.XChain().AwaitTransactionAcceptance(funder.client, exportTxId, constants.TimeoutDuration); err != nil {
+			return stacktrace.Propagate(err, "An error occurred waiting for the X-Chain export for address '%v' to be accepted", address.Hex())
+		}
+
+		importTxId, err := funder.client.CChainAPI().Import(funder.userPass, address.Hex(), "X")
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred importing AVAX to C-Chain address '%v'", address.Hex())
+		}
+		if err := chainhelper.CChain().AwaitTransactionAcceptance(funder.client, importTxId, constants.TimeoutDuration); err != nil {
+			return stacktrace.Propagate(err, "An error occurred waiting for the C-Chain import to address '%v' to be accepted", address.Hex())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/palantir/stacktrace"
+)
+
+// Geth clients for a single node's C-Chain, over both of the transports the node exposes
+type NodeCChainClients struct {
+	// Needed for subscriptions (e.g. new heads, logs)
+	Websocket *ethclient.Client
+
+	Http *ethclient.Client
+
+	// The RPC client underlying Http, for reading fields that go-ethereum's types get wrong for the C-Chain (e.g. block
+	//  hashes, which go-ethereum's header type can't compute because it lacks the C-Chain's extra header fields)
+	HttpRpc *rpc.Client
+}
+
+func newNodeCChainClients(ctx context.Context, endpoint NodeEndpoint) (*NodeCChainClients, error) {
+	websocketUri := endpoint.GetCChainWebsocketUri()
+	websocketClient, err := ethclient.DialContext(ctx, websocketUri)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting an ethclient for URI '%v'", websocketUri)
+	}
+	httpUri := endpoint.GetCChainHttpUri()
+	httpRpcClient, err := rpc.DialContext(ctx, httpUri)
+	if err != nil {
+		websocketClient.Close()
+		return nil, stacktrace.Propagate(err, "An error occurred getting an RPC client for URI '%v'", httpUri)
+	}
+	return &NodeCChainClients{
+		Websocket: websocketClient,
+		Http:      ethclient.NewClient(httpRpcClient),
+		HttpRpc:   httpRpcClient,
+	}, nil
+}
@@ -0,0 +1,24 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import "fmt"
+
+// Where a node in the network can be reached; the ports come from the node's running service rather than being assumed,
+//  so that nodes launched on non-default ports still work
+type NodeEndpoint struct {
+	IPAddress   string
+	HTTPPort    int
+	StakingPort int
+}
+
+func (endpoint NodeEndpoint) GetCChainWebsocketUri() string {
+	return fmt.Sprintf("ws://%s:%d/ext/bc/C/ws", endpoint.IPAddress, endpoint.HTTPPort)
+}
+
+func (endpoint NodeEndpoint) GetCChainHttpUri() string {
+	return fmt.Sprintf("http://%s:%d/ext/bc/C/rpc", endpoint.IPAddress, endpoint.HTTPPort)
+}
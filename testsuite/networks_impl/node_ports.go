@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"fmt"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// The ports that avalanchego-kurtosis launches nodes with
+	defaultNodeHttpPort    = 9650
+	defaultNodeStakingPort = 9651
+
+	minPort = 1
+	maxPort = 65535
+
+	httpPortFlag    = "--http-port"
+	stakingPortFlag = "--staking-port"
+)
+
+// Ports that every node in the network listens on; they're passed to each node as flags, exposed on its container, and
+//  used to build every URL that the network connects to the node with
+type NodePorts struct {
+	HTTPPort    int	`json:"httpPort"`
+	StakingPort int	`json:"stakingPort"`
+}
+
+func NewDefaultNodePorts() NodePorts {
+	return NodePorts{
+		HTTPPort:    defaultNodeHttpPort,
+		StakingPort: defaultNodeStakingPort,
+	}
+}
+
+func (ports NodePorts) Validate() error {
+	if ports.HTTPPort < minPort || ports.HTTPPort > maxPort {
+		return stacktrace.NewError("Node HTTP port must be in the range [%v, %v], but was %v", minPort, maxPort, ports.HTTPPort)
+	}
+	if ports.StakingPort < minPort || ports.StakingPort > maxPort {
+		return stacktrace.NewError("Node staking port must be in the range [%v, %v], but was %v", minPort, maxPort, ports.StakingPort)
+	}
+	if ports.HTTPPort == ports.StakingPort {
+		return stacktrace.NewError("Node HTTP and staking ports must differ, but both were %v", ports.HTTPPort)
+	}
+	return nil
+}
+
+func (ports NodePorts) getNodeFlags() []string {
+	return []string{
+		fmt.Sprintf("%v=%d", httpPortFlag, ports.HTTPPort),
+		fmt.Sprintf("%v=%d", stakingPortFlag, ports.StakingPort),
+	}
+}
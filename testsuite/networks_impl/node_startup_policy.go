@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"github.com/palantir/stacktrace"
+	"time"
+)
+
+const (
+	defaultTimeBetweenNodeStartupPolls = 5 * time.Second
+	defaultMaxNumNodeStartupPolls = 30
+)
+
+// Controls how long the network waits for each node to report that it's bootstrapped
+type NodeStartupPolicy struct {
+	TimeBetweenPolls time.Duration
+	MaxNumPolls int
+}
+
+func NewDefaultNodeStartupPolicy() NodeStartupPolicy {
+	return NodeStartupPolicy{
+		TimeBetweenPolls: defaultTimeBetweenNodeStartupPolls,
+		MaxNumPolls:      defaultMaxNumNodeStartupPolls,
+	}
+}
+
+func (policy NodeStartupPolicy) Validate() error {
+	if policy.TimeBetweenPolls <= 0 {
+		return stacktrace.NewError("Time between node startup polls must be > 0, but was %v", policy.TimeBetweenPolls)
+	}
+	if policy.MaxNumPolls < 1 {
+		return stacktrace.NewError("Max number of node startup polls must be >= 1, but was %v", policy.MaxNumPolls)
+	}
+	return nil
+}
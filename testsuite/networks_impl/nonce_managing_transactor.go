@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sync"
+)
+
+// Wraps a transactor so that many goroutines can send transactions from the same account at once
+// By default, the generated bindings ask the node for the pending nonce before every transaction, so concurrent sends
+//  get the same nonce and fail with "nonce too low" or replacement errors; this hands out nonces locally instead, and
+//  only goes back to the node after a send fails (since a failed send leaves a gap that must be filled)
+type NonceManagingTransactor struct {
+	// Used to resync the nonce with the node
+	client bind.ContractTransactor
+
+	transactor *bind.TransactOpts
+
+	gasStrategy GasStrategy
+
+	// Nil if gas usage isn't being recorded
+	gasUsageRecorder *GasUsageRecorder
+
+	mutex sync.Mutex
+
+	// Only valid when isNonceSynced is true
+	nextNonce     uint64
+	isNonceSynced bool
+}
+
+func NewNonceManagingTransactor(
+		client bind.ContractTransactor,
+		transactor *bind.TransactOpts,
+		gasStrategy GasStrategy,
+		gasUsageRecorder *GasUsageRecorder) *NonceManagingTransactor {
+	return &NonceManagingTransactor{
+		client:           client,
+		transactor:       transactor,
+		gasStrategy:      gasStrategy,
+		gasUsageRecorder: gasUsageRecorder,
+		nextNonce:        0,
+		isNonceSynced:    false,
+	}
+}
+
+func (nonceManager *NonceManagingTransactor) GetAddress() common.Address {
+	return nonceManager.transactor.From
+}
+
+// Reserves a nonce and calls the given function with transact opts using it, which can be passed straight to the
+//  generated bindings, e.g.:
+//
+//  tx, err := nonceManager.Transact(ctx, "SimpleStorage", "Set", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+//      return storageContract.Set(opts, value)
+//  })
+//
+// If the function returns an error, the nonce is resynced with the node before the next transaction
+// The gas price and limit are chosen by the transactor's gas strategy, and logged along with the transaction hash
+// The contract and method names label the transaction in the gas usage report; by convention, deployments use the
+//  binding's function name (e.g. "DeploySimpleStorage")
+func (nonceManager *NonceManagingTransactor) Transact(
+		ctx context.Context,
+		contractName string,
+		methodName string,
+		transactFunc func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	label := &transactionLabel{contract: contractName, method: methodName}
+	return nonceManager.transact(ctx, contractName + "." + methodName, label, transactFunc)
+}
+
+// Like Transact, but with a free-form description for the log, and a nil label for transactions that shouldn't show up
+//  in the gas usage report (e.g. funding transfers)
+func (nonceManager *NonceManagingTransactor) transact(
+		ctx context.Context,
+		description string,
+		label *transactionLabel,
+		transactFunc func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	opts := *nonceManager.transactor
+	opts.Context = ctx
+	if err := nonceManager.gasStrategy.apply(ctx, nonceManager.client, &opts); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred applying gas strategy '%v'", nonceManager.gasStrategy)
+	}
+
+	// The nonce is reserved last, so that a failure above doesn't leave a gap
+	nonce, err := nonceManager.reserveNonce(ctx)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred reserving a nonce for account '%v'", nonceManager.GetAddress().Hex())
+	}
+
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+	tx, err := transactFunc(&opts)
+	if err != nil {
+		nonceManager.markNonceUnsynced()
+		return nil, stacktrace.Propagate(
+			err,
+			"An error occurred sending the transaction with nonce %v from account '%v'; the nonce will be resynced before the next transaction",
+			nonce,
+			nonceManager.GetAddress().Hex())
+	}
+	if nonceManager.gasUsageRecorder != nil && label != nil {
+		nonceManager.gasUsageRecorder.labelSentTransaction(tx.Hash(), label.contract, label.method)
+	}
+	logrus.Infof(
+		"Sent %v transaction '%v' with nonce %v, gas price %v wei, and gas limit %v (gas strategy: %v)",
+		description,
+		tx.Hash().Hex(),
+		nonce,
+		tx.GasPrice(),
+		tx.Gas(),
+		nonceManager.gasStrategy)
+	return tx, nil
+}
+
+func (nonceManager *NonceManagingTransactor) reserveNonce(ctx context.Context) (uint64, error) {
+	nonceManager.mutex.Lock()
+	defer nonceManager.mutex.Unlock()
+
+	if !nonceManager.isNonceSynced {
+		pendingNonce, err := nonceManager.client.PendingNonceAt(ctx, nonceManager.GetAddress())
+		if err != nil {
+			return 0, stacktrace.Propagate(err, "An error occurred getting the pending nonce from the node")
+		}
+		logrus.Debugf("Synced nonce for account '%v' to %v", nonceManager.GetAddress().Hex(), pendingNonce)
+		nonceManager.nextNonce = pendingNonce
+		nonceManager.isNonceSynced = true
+	}
+	result := nonceManager.nextNonce
+	nonceManager.nextNonce++
+	return result, nil
+}
+
+func (nonceManager *NonceManagingTransactor) markNonceUnsynced() {
+	nonceManager.mutex.Lock()
+	defer nonceManager.mutex.Unlock()
+	nonceManager.isNonceSynced = false
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"time"
+)
+
+const (
+	// Same as the C-Chain's block gas limit
+	simulatedBlockGasLimit = 8000000
+
+	// Genesis balances of the transactor and of the faucet that funds the accounts returned by GetFundedAccounts;
+	//  large enough that no test should run out
+	simulatedGenesisBalanceAvax = 1000000000
+
+	simulatedTestFundedAccountBalance = 1000 * units.Avax
+
+	// Transactions are mined as soon as they're sent, so this is generous
+	SimulatedTestRunTimeout = 60 * time.Second
+)
+
+// A SmartContractBackend backed by an in-memory chain, so that test logic can run in a plain 'go test' without Docker
+//  or Kurtosis
+// Every transaction is mined in its own block as soon as it's sent, so transactions are accepted as soon as
+//  WaitForTransactionAccepted is called; there's only one "node", so state is always consistent
+type SimulatedSmartContractBackend struct {
+	backend *autoMiningSimulatedBackend
+
+	fundedAccountBalance uint64
+
+	gasStrategy GasStrategy
+
+	gasUsageRecorder *GasUsageRecorder
+
+	accountKeyGenerator *accountKeyGenerator
+
+	// Funded in the genesis; sends the funds for GetFundedAccounts, from an account of its own so that funding doesn't
+	//  take nonces from the transactor
+	faucet *NonceManagingTransactor
+
+	nonceManagingTransactor *NonceManagingTransactor
+}
+
+// Only the config's funded account balance, account key source, and gas strategy are used; the rest describes the
+//  shape of an Avalanche network, which doesn't apply here
+func NewSimulatedSmartContractBackend(config SmartContractAvalancheNetworkConfig) (*SimulatedSmartContractBackend, error) {
+	if err := config.Gas.Validate(); err != nil {
+		return nil, stacktrace.Propagate(err, "The gas strategy is invalid")
+	}
+	accountKeyGenerator, err := newAccountKeyGenerator(config.AccountKeys)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred creating the account key generator")
+	}
+
+	// As on the Avalanche network, the transactor gets the first key
+	transactorPrivKey, transactorKeyOrigin, err := accountKeyGenerator.nextKey()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the private key for the transactor's account")
+	}
+	transactorAccount := newFundedAccount(transactorAccountName, transactorPrivKey)
+	faucetKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred generating the faucet's private key")
+	}
+
+	genesisBalance := new(big.Int).Mul(big.NewInt(simulatedGenesisBalanceAvax), big.NewInt(params.Ether))
+	alloc := core.GenesisAlloc{
+		transactorAccount.Address:                  {Balance: genesisBalance},
+		crypto.PubkeyToAddress(faucetKey.PublicKey): {Balance: genesisBalance},
+	}
+	backend := newAutoMiningSimulatedBackend(alloc, simulatedBlockGasLimit)
+	logrus.Infof(
+		"Simulated C-Chain created with transactor address '%v' funded using %v key",
+		transactorAccount.Address.Hex(),
+		transactorKeyOrigin)
+
+	gasUsageRecorder := NewGasUsageRecorder()
+	return &SimulatedSmartContractBackend{
+		backend:                 backend,
+		fundedAccountBalance:    config.FundedAccountBalance,
+		gasStrategy:             config.Gas,
+		gasUsageRecorder:        gasUsageRecorder,
+		accountKeyGenerator:     accountKeyGenerator,
+		faucet:                  NewNonceManagingTransactor(backend, bind.NewKeyedTransactor(faucetKey), config.Gas, nil),
+		nonceManagingTransactor: NewNonceManagingTransactor(backend, transactorAccount.Transactor, config.Gas, gasUsageRecorder),
+	}, nil
+}
+
+func (simulated *SimulatedSmartContractBackend) GetClient() CChainClient {
+	return simulated.backend
+}
+
+func (simulated *SimulatedSmartContractBackend) GetChainId(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(params.AllEthashProtocolChanges.ChainID), nil
+}
+
+func (simulated *SimulatedSmartContractBackend) GetNonceManagingTransactor() *NonceManagingTransactor {
+	return simulated.nonceManagingTransactor
+}
+
+// Creates an account for each of the given names and funds it from the faucet with the configured balance
+func (simulated *SimulatedSmartContractBackend) GetFundedAccounts(ctx context.Context, accountNames ...string) ([]*FundedAccount, error) {
+	accounts, err := fundAccountsByTransfer(
+		ctx,
+		simulated.backend,
+		simulated.faucet,
+		simulated.accountKeyGenerator,
+		simulated.fundedAccountBalance,
+		accountNames)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred funding accounts %v from the faucet", accountNames)
+	}
+	return accounts, nil
+}
+
+// Only one should be created per account, else their nonces will collide
+func (simulated *SimulatedSmartContractBackend) NewAccountTransactor(account *FundedAccount) (*NonceManagingTransactor, error) {
+	return NewNonceManagingTransactor(simulated.backend, account.Transactor, simulated.gasStrategy, simulated.gasUsageRecorder), nil
+}
+
+// Transactions are mined as soon as they're sent, so this only waits for the receipt; as on the Avalanche network,
+//  reverted transactions return a *TransactionRevertedError and gas usage gets recorded
+// NOTE: SimulatedBackend can only make calls against the latest block, so revert reasons are determined against the
+//  latest state rather than the state the transaction executed on top of
+func (simulated *SimulatedSmartContractBackend) WaitForTransactionAccepted(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := waitForReceipt(ctx, simulated.backend, txHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred waiting for transaction '%v' to be included in a block", txHash.Hex())
+	}
+	logrus.Debugf("Transaction '%v' included in block %v", txHash.Hex(), receipt.BlockNumber)
+	simulated.gasUsageRecorder.recordReceipt(receipt)
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		return receipt, getTransactionRevertedError(ctx, simulated.backend, simulated.backend.signer, receipt, nil)
+	}
+	return receipt, nil
+}
+
+// Blocks are mined as soon as transactions are sent, so this only blocks while the transaction that mines the block is
+//  still being sent
+func (simulated *SimulatedSmartContractBackend) WaitForBlockNumber(ctx context.Context, blockNumber uint64) error {
+	if err := waitForBlockNumber(ctx, simulated.backend, blockNumber); err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for the simulated chain to mine block %v", blockNumber)
+	}
+	return nil
+}
+
+// There's only one copy of the state, so this only checks that the required block has been mined
+func (simulated *SimulatedSmartContractBackend) AssertCChainStateConsistent(ctx context.Context, query CChainStateQuery) error {
+	if err := assertSingleClientReachedBlock(ctx, simulated.backend, query); err != nil {
+		return stacktrace.Propagate(err, "The simulated chain hasn't mined the queried block")
+	}
+	return nil
+}
+
+func (simulated *SimulatedSmartContractBackend) GetGasUsageRecorder() *GasUsageRecorder {
+	return simulated.gasUsageRecorder
+}
+
+// Releases the simulated chain's resources
+func (simulated *SimulatedSmartContractBackend) Close() error {
+	if err := simulated.backend.Close(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred closing the simulated backend")
+	}
+	return nil
+}
+
+// Creates a backend for running a test's logic in a plain 'go test', returning it along with the config it was created
+//  from, which the test should be given as its network config
+func NewSimulatedTestBackend() (*SimulatedSmartContractBackend, SmartContractAvalancheNetworkConfig, error) {
+	config := SmartContractAvalancheNetworkConfig{
+		FundedAccountBalance: simulatedTestFundedAccountBalance,
+		Gas:                  NewDefaultGasStrategy(),
+	}
+	backend, err := NewSimulatedSmartContractBackend(config)
+	if err != nil {
+		return nil, SmartContractAvalancheNetworkConfig{}, stacktrace.Propagate(err, "An error occurred creating the simulated backend")
+	}
+	return backend, config, nil
+}
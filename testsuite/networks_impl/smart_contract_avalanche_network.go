@@ -1,53 +1,90 @@
 package networks_impl
 
 import (
+	"context"
 	"fmt"
 	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/avalanche/libs/builder/networkbuilder"
-	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/avalanche/libs/builder/topology"
 	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/avalanche/libs/constants"
 	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/avalanche/tests/testconstants"
-	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/kurtosis/networksavalanche"
-	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego-kurtosis/kurtosis/kurtosis/servicesavalanche/avalanchegonode"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
 	"strings"
-	"time"
+	"sync"
 )
 
 const (
-	// TODO Get this from the node config somehow, rather than hardcoding it
-	nodeHttpPort = 9650
-
 	// NOTE: This has to be 1-indexed because NodeInitializer requires it, rather than 0-indexed
 	initialBootstrapperIdIdx = 1
 
 	hexStrIndicatorLeader = "0x"
 
-	timeBetweenNodeStartupPolls = 5 * time.Second
-	maxNumNodeStartupPolls = 30
+	// Name of the account backing the transactor returned by GetFundedCChainClientAndTransactor
+	transactorAccountName = "transactor"
 )
 
 type SmartContractAvalancheNetwork struct {
 	avalancheImage string
 
-	avalancheNetwork *networksavalanche.AvalancheNetwork
+	snowParams SnowParams
+
+	nodePorts NodePorts
+
+	nodeStartupPolicy NodeStartupPolicy
+
+	fundedAccountBalance uint64
+
+	accountKeySource AccountKeySource
+
+	gasStrategy GasStrategy
+
+	// Records the gas used by every transaction sent through the network's NonceManagingTransactors
+	gasUsageRecorder *GasUsageRecorder
+
+	// Created during setup, from the account key source
+	accountKeyGenerator *accountKeyGenerator
+
+	networkCtx *networks.NetworkContext
+
+	// The nodes are created here rather than through the avalanchego-kurtosis AvalancheNetwork, because it launches
+	//  them with a container config that can't be given any node flags besides the Snow sample and quorum sizes
+	// Keyed by node ID
+	nodes map[services.ServiceID]*avalanchegonode.NodeAPIService
+
+	// Guards the nodes map, since the non-bootstrap nodes are created concurrently; a pointer because the network is
+	//  used by value in places
+	nodesMutex *sync.Mutex
 
 	networkConfiguration *networkbuilder.Network
 
+	// Filled in once the nodes are up, keyed by node ID
+	nodeEndpoints map[string]NodeEndpoint
+
+	// Filled in once the nodes are up, keyed by node ID
+	cChainClients map[string]*NodeCChainClients
+
+	// Moves funds out of the genesis allocation, which is used to fund new C-Chain accounts
+	genesisFunder *genesisFunder
+
 	transactor *bind.TransactOpts
 	gethClient *ethclient.Client
+
+	// Wraps the transactor; shared so that every caller draws nonces from the same counter
+	nonceManagingTransactor *NonceManagingTransactor
 }
 
-func NewSmartContractAvalancheNetwork(avalancheImage string, networkCtx *networks.NetworkContext) *SmartContractAvalancheNetwork {
+func NewSmartContractAvalancheNetwork(config SmartContractAvalancheNetworkConfig, networkCtx *networks.NetworkContext) *SmartContractAvalancheNetwork {
+	avalancheImage := config.AvalancheImage
 	networkConfiguration := networkbuilder.New().
 		Image(avalancheImage).
-		SnowSize(3, 3)
+		SnowSize(config.Snow.SampleSize, config.Snow.QuorumSize)
 
 	i := initialBootstrapperIdIdx
 	for _, staker := range constants.DefaultLocalNetGenesisConfig.Stakers {
@@ -65,149 +102,421 @@ func NewSmartContractAvalancheNetwork(avalancheImage string, networkCtx *network
 	}
 	networkConfiguration.HasBootstrapNodes(true)
 
+	// Non-bootstrap nodes get no bootstrap node ID, which makes them connect to every bootstrap node
+	// They have staking enabled because that's how they talk to the bootstrap stakers, but without a staker key and TLS
+	//  cert of their own, each generates a fresh identity that isn't in the validator set
+	for i := 1; i <= config.NumAdditionalNodes; i++ {
+		nodeConfig := networkbuilder.NewNode(getAdditionalNodeId(i)).
+			Image(avalancheImage).
+			IsStaking(true)
+		networkConfiguration.AddNode(nodeConfig)
+	}
+
 	result := &SmartContractAvalancheNetwork{
 		avalancheImage:                 avalancheImage,
-		avalancheNetwork: networksavalanche.NewAvalancheNetwork(networkCtx, avalancheImage),
+		snowParams: config.Snow,
+		nodePorts: config.NodePorts,
+		nodeStartupPolicy: config.NodeStartup,
+		fundedAccountBalance: config.FundedAccountBalance,
+		accountKeySource: config.AccountKeys,
+		gasStrategy: config.Gas,
+		gasUsageRecorder: NewGasUsageRecorder(),
+		accountKeyGenerator: nil,
+		networkCtx: networkCtx,
+		nodes: map[services.ServiceID]*avalanchegonode.NodeAPIService{},
+		nodesMutex: &sync.Mutex{},
 		networkConfiguration:           networkConfiguration,
+		nodeEndpoints: map[string]NodeEndpoint{},
+		cChainClients: map[string]*NodeCChainClients{},
+		genesisFunder: nil,
 		transactor: nil,
 		gethClient: nil,
+		nonceManagingTransactor: nil,
 	}
 	return result
 }
 
 // Prepares an Avalanche network for smart contract deployment by starting it, creating a C-Chain address, funding it, etc.
 // This function is expected to be used in the Test.Setup phase, with GetTransactor and GetGethClient used in Test.Run phase
-func (network *SmartContractAvalancheNetwork) SetupAvalancheNetwork() error {
+// Setup stops as soon as the context is done; the only exception is the genesis funding, since the avalanchego API
+//  clients take no context, so the context is checked before each funding step instead
+func (network *SmartContractAvalancheNetwork) SetupAvalancheNetwork(ctx context.Context) error {
 	if network.transactor != nil || network.gethClient != nil {
 		return stacktrace.NewError("Avalanche network already started")
 	}
+	logrus.Infof("Using Snow consensus params: %+v", network.snowParams)
+
+	if err := network.nodeStartupPolicy.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid node startup policy")
+	}
+	accountKeyGenerator, err := newAccountKeyGenerator(network.accountKeySource)
+	if err != nil {
+		return stacktrace.Propagate(err, "Invalid account key source")
+	}
+	network.accountKeyGenerator = accountKeyGenerator
+	if err := network.gasStrategy.Validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid gas strategy")
+	}
 
-	logrus.Info("Launching bootstrap nodes...")
-	bootstrapNodeCheckers := map[string]*services.DefaultAvailabilityChecker{}
 	// TODO We have to do this "start from 1" indexing because the NodeInitializer currently requires a specific ServiceID pattern,
 	//  instantiated in a particular order (see https://github.com/ava-labs/avalanchego-kurtosis/issues/6 )
+	bootstrapNodeIds := []string{}
 	for i := 1; i <= len(constants.DefaultLocalNetGenesisConfig.Stakers); i++ {
-		id := getBootstrapNodeId(i)
-		nodeConfig, found := network.networkConfiguration.Nodes[id]
+		bootstrapNodeIds = append(bootstrapNodeIds, getBootstrapNodeId(i))
+	}
+	logrus.Info("Launching bootstrap nodes and waiting for them to become available...")
+	// Each bootstrap node connects to the ones before it, so they have to be launched in order
+	if err := network.launchNodesAndWaitForAvailability(ctx, bootstrapNodeIds, false); err != nil {
+		return stacktrace.Propagate(err, "An error occurred starting the bootstrap nodes")
+	}
+	logrus.Info("Bootstrap nodes available")
+
+	nonBootstrapNodeIds := []string{}
+	for id, nodeConfig := range network.networkConfiguration.Nodes {
+		if !nodeConfig.IsBootstrapNode() {
+			nonBootstrapNodeIds = append(nonBootstrapNodeIds, id)
+		}
+	}
+	sort.Strings(nonBootstrapNodeIds)
+	logrus.Infof("Launching %v non-bootstrap nodes and waiting for them to become available...", len(nonBootstrapNodeIds))
+	if err := network.launchNodesAndWaitForAvailability(ctx, nonBootstrapNodeIds, true); err != nil {
+		return stacktrace.Propagate(err, "An error occurred starting the non-bootstrap nodes")
+	}
+	logrus.Info("Non-bootstrap nodes available")
+
+	for id := range network.networkConfiguration.Nodes {
+		node, found := network.nodes[services.ServiceID(id)]
 		if !found {
-			return stacktrace.NewError("Expected a node config for ID '%v', but none was found", id)
+			return stacktrace.NewError("Expected node '%v' to have been launched, but it wasn't", id)
 		}
-		_, checker, err := network.avalancheNetwork.CreateNodeNoCheck(network.networkConfiguration, nodeConfig)
-		if err != nil {
-			return stacktrace.Propagate(err, "An error occurred creating bootstrapper node with ID '%v'", id)
+		network.nodeEndpoints[id] = NodeEndpoint{
+			IPAddress:   node.GetIPAddress(),
+			HTTPPort:    node.GetHTTPPort(),
+			StakingPort: node.GetStakingPort(),
 		}
-		bootstrapNodeCheckers[id] = checker
 	}
-	logrus.Info("Bootstrap nodes launched")
 
-	logrus.Info("Waiting for bootstrap nodes to become available...")
-	for id, checker := range bootstrapNodeCheckers {
-		if err := checker.WaitForStartup(timeBetweenNodeStartupPolls, maxNumNodeStartupPolls); err != nil {
-			return stacktrace.Propagate(err, "An error occurred waiting for bootstrapper node '%v' to become available", id)
-		}
+	if err := ctx.Err(); err != nil {
+		return stacktrace.Propagate(err, "Gave up setting up the network before taking control of the genesis funds")
+	}
+	firstNodeId := getBootstrapNodeId(initialBootstrapperIdIdx)
+	firstNode, found := network.nodes[services.ServiceID(firstNodeId)]
+	if !found {
+		return stacktrace.NewError("Expected node '%v' to have been launched, but it wasn't", firstNodeId)
+	}
+	genesisFunder, err := newGenesisFunder(firstNode.GetNodeClient(), testconstants.GenesisUsername, testconstants.GenesisPassword)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred taking control of the genesis funds on node '%v'", firstNodeId)
 	}
-	logrus.Info("Bootstrap nodes available")
 
-	logrus.Info("Launching non-bootstrap nodes...")
-	nonBootstrapNodeCheckers := map[string]*services.DefaultAvailabilityChecker{}
-	for id, nodeConfig := range network.networkConfiguration.Nodes {
-		if nodeConfig.IsBootstrapNode() {
-			continue
-		}
-		_, checker, err := network.avalancheNetwork.CreateNodeNoCheck(network.networkConfiguration, nodeConfig)
+	if err := ctx.Err(); err != nil {
+		return stacktrace.Propagate(err, "Gave up setting up the network before funding the transactor")
+	}
+	logrus.Info("Creating funded C-Chain account for the transactor...")
+	transactorPrivKey, transactorKeyOrigin, err := network.accountKeyGenerator.nextKey()
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the private key for the transactor's C-Chain account")
+	}
+	transactorAccount := newFundedAccount(transactorAccountName, transactorPrivKey)
+	if err := genesisFunder.moveHalfOfBalanceToCChain(transactorAccount.Address, testconstants.TxFee); err != nil {
+		return stacktrace.Propagate(err, "An error occurred funding the transactor's C-Chain account")
+	}
+	transactor := transactorAccount.Transactor
+	logrus.Infof(
+		"C-Chain address '%v' created and funded using %v key",
+		transactorAccount.Address.Hex(),
+		transactorKeyOrigin)
+
+	logrus.Info("Creating Geth clients for every node...")
+	for id, endpoint := range network.nodeEndpoints {
+		clients, err := newNodeCChainClients(ctx, endpoint)
 		if err != nil {
-			return stacktrace.Propagate(err, "An error occurred creating new node")
+			return stacktrace.Propagate(err, "An error occurred creating the Geth clients for node '%v'", id)
 		}
-		nonBootstrapNodeCheckers[id] = checker
+		network.cChainClients[id] = clients
+	}
+	gethClient := network.cChainClients[firstNodeId].Websocket
+	logrus.Info("Geth clients created")
+
+	network.genesisFunder = genesisFunder
+	network.transactor = transactor
+	network.gethClient = gethClient
+	network.nonceManagingTransactor = NewNonceManagingTransactor(gethClient, transactor, network.gasStrategy, network.gasUsageRecorder)
+
+	return nil
+}
+
+// Returns a Geth client for interacting with the Avalanche network's C-Chain, as well as a transactor required for making
+//  requests using the Geth client
+func (network SmartContractAvalancheNetwork) GetFundedCChainClientAndTransactor() (*ethclient.Client, *bind.TransactOpts) {
+	return network.gethClient, network.transactor
+}
+
+// Returns the same client as GetFundedCChainClientAndTransactor, as a CChainClient
+func (network SmartContractAvalancheNetwork) GetClient() CChainClient {
+	// A nil *ethclient.Client would otherwise become a non-nil interface
+	if network.gethClient == nil {
+		return nil
 	}
-	logrus.Info("Non-bootstrap nodes launched")
+	return network.gethClient
+}
+
+func (network SmartContractAvalancheNetwork) GetChainId(ctx context.Context) (*big.Int, error) {
+	if network.gethClient == nil {
+		return nil, stacktrace.NewError("The chain ID can't be retrieved until the Avalanche network has been set up")
+	}
+	chainId, err := network.gethClient.ChainID(ctx)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the chain ID from the C-Chain")
+	}
+	return chainId, nil
+}
+
+// Returns a wrapper around the funded transactor that can safely be used to send transactions from many goroutines at
+//  once; all transactions from the transactor's account should go through it, else its nonces will collide with theirs
+func (network SmartContractAvalancheNetwork) GetNonceManagingTransactor() *NonceManagingTransactor {
+	return network.nonceManagingTransactor
+}
 
-	logrus.Info("Waiting for non-bootstrap nodes to become available...")
-	for id, checker := range nonBootstrapNodeCheckers {
-		if err := checker.WaitForStartup(timeBetweenNodeStartupPolls, maxNumNodeStartupPolls); err != nil {
-			return stacktrace.Propagate(err, "An error occurred waiting for non-bootstrapper node '%v' to become available", id)
+// Launches the given nodes and waits for all of them to become available, returning an error describing every node that
+//  failed rather than just the first
+// If launchConcurrently is false, the nodes are launched one at a time in the given order, with each node's availability
+//  wait (which is the slow part) starting as soon as it's launched; otherwise every node is launched and waited on in
+//  its own goroutine
+// NOTE: The Kurtosis NetworkContext holds a lock while it adds a service, so concurrent launches still reach the
+//  Kurtosis API one at a time; what overlaps is everything around that, including every availability wait
+func (network *SmartContractAvalancheNetwork) launchNodesAndWaitForAvailability(ctx context.Context, nodeIds []string, launchConcurrently bool) error {
+	policy := network.nodeStartupPolicy
+
+	var waitGroup sync.WaitGroup
+	var nodeErrsMutex sync.Mutex
+	nodeErrs := map[string]error{}
+	recordNodeErr := func(id string, err error) {
+		nodeErrsMutex.Lock()
+		defer nodeErrsMutex.Unlock()
+		nodeErrs[id] = err
+	}
+	waitForNode := func(id string, checker services.AvailabilityChecker) {
+		if err := checker.WaitForStartup(policy.TimeBetweenPolls, policy.MaxNumPolls); err != nil {
+			recordNodeErr(id, stacktrace.Propagate(err, "An error occurred waiting for node '%v' to become available", id))
+			return
 		}
+		logrus.Debugf("Node '%v' available", id)
 	}
-	logrus.Info("Non-bootstrap nodes available")
 
-	topo := topology.New(network.avalancheNetwork)
+	for _, id := range nodeIds {
+		if err := ctx.Err(); err != nil {
+			recordNodeErr(id, stacktrace.Propagate(err, "Gave up before launching node '%v'", id))
+			break
+		}
+		nodeConfig, found := network.networkConfiguration.Nodes[id]
+		if !found {
+			recordNodeErr(id, stacktrace.NewError("Expected a node config for ID '%v', but none was found", id))
+			break
+		}
+
+		if launchConcurrently {
+			waitGroup.Add(1)
+			go func(id string, nodeConfig *networkbuilder.Node) {
+				defer waitGroup.Done()
+				checker, err := network.createNode(nodeConfig)
+				if err != nil {
+					recordNodeErr(id, stacktrace.Propagate(err, "An error occurred creating node with ID '%v'", id))
+					return
+				}
+				logrus.Debugf("Node '%v' launched", id)
+				waitForNode(id, checker)
+			}(id, nodeConfig)
+			continue
+		}
 
-	firstValidatorId := ""
-	for _, node := range network.networkConfiguration.Nodes {
-		topo.AddNode(node.ID, node.ID, constants.DefaultPassword)
-		if firstValidatorId == "" {
-			firstValidatorId = node.ID
+		checker, err := network.createNode(nodeConfig)
+		if err != nil {
+			// Later nodes may depend on this one (e.g. bootstrap nodes connect to all the ones before them), so we stop
+			//  launching but still wait on the nodes that were already launched so their errors get reported too
+			recordNodeErr(id, stacktrace.Propagate(err, "An error occurred creating node with ID '%v'", id))
+			break
 		}
+		logrus.Debugf("Node '%v' launched", id)
+
+		waitGroup.Add(1)
+		go func(id string, checker services.AvailabilityChecker) {
+			defer waitGroup.Done()
+			waitForNode(id, checker)
+		}(id, checker)
 	}
-	topo.AddGenesis(
-		firstValidatorId,
-		testconstants.GenesisUsername,
-		testconstants.GenesisPassword,
-	)
 
-	firstNodeId := getBootstrapNodeId(initialBootstrapperIdIdx)
-	firstNode := topo.Node(firstNodeId)
-	firstNodeAvalancheGoClient := firstNode.GetClient()
+	// The availability checker can't be cancelled, so on cancellation we stop waiting on it and let it finish polling
+	//  in the background
+	allWaitsDone := make(chan struct{})
+	go func() {
+		waitGroup.Wait()
+		close(allWaitsDone)
+	}()
+	select {
+	case <-allWaitsDone:
+	case <-ctx.Done():
+		return stacktrace.Propagate(ctx.Err(), "Gave up waiting for nodes %v to become available", nodeIds)
+	}
 
-	logrus.Info("Creating C-Chain address...")
-	firstNodeCChainApi := firstNodeAvalancheGoClient.CChainAPI()
-	// Client ...
-	type Client struct {
-		requester rpc.EndpointRequester
+	if len(nodeErrs) == 0 {
+		return nil
+	}
+	failedNodeIds := []string{}
+	for id := range nodeErrs {
+		failedNodeIds = append(failedNodeIds, id)
+	}
+	sort.Strings(failedNodeIds)
+	errStrs := []string{}
+	for _, id := range failedNodeIds {
+		errStrs = append(errStrs, fmt.Sprintf("Node '%v':\n%v", id, nodeErrs[id]))
 	}
-	xChainPrivateKey, err := firstNodeAvalancheGoClient.XChainAPI().ExportKey(firstNode.UserPass, firstNode.XAddress)
+	return stacktrace.NewError(
+		"%v of %v nodes failed to start:\n%v",
+		len(failedNodeIds),
+		len(nodeIds),
+		strings.Join(errStrs, "\n\n"))
+}
+
+// Launches the node without waiting for it to become available; safe to call from several goroutines at once
+func (network *SmartContractAvalancheNetwork) createNode(nodeConfig *networkbuilder.Node) (services.AvailabilityChecker, error) {
+	serviceId := services.ServiceID(nodeConfig.ID)
+	existingNodes, err := network.getNodesSnapshot(serviceId)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred exporting the node's private key from the X-Chain")
+		return nil, stacktrace.Propagate(err, "Node '%v' can't be created", nodeConfig.ID)
 	}
-	logrus.Debugf("X-Chain private key: %v", xChainPrivateKey)
-	cChainAddrStr, err := firstNodeAvalancheGoClient.CChainAPI().ImportKey(firstNode.UserPass, xChainPrivateKey)
+
+	// The avalanchego-kurtosis factory looks up the bootstrap nodes that this node connects to in the map of nodes, so it
+	//  gets a copy that other launches can't write to while it reads
+	wrappedFactory := avalanchegonode.NewAvalancheGoContainerConfigFactory(network.networkConfiguration, nodeConfig, existingNodes)
+	configFactory := newAvalancheNodeContainerConfigFactory(wrappedFactory, network.nodePorts, network.snowParams.getAdditionalNodeFlags())
+	uncastedService, _, checker, err := network.networkCtx.AddService(serviceId, configFactory)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred importing the node's private key to the C-Chain")
+		return nil, stacktrace.Propagate(err, "An error occurred adding the service for node '%v'", nodeConfig.ID)
+	}
+	castedService, ok := uncastedService.(*avalanchegonode.NodeAPIService)
+	if !ok {
+		return nil, stacktrace.NewError("Expected the service for node '%v' to be a node API service, but it wasn't", nodeConfig.ID)
+	}
+	network.nodesMutex.Lock()
+	defer network.nodesMutex.Unlock()
+	network.nodes[serviceId] = castedService
+	return checker, nil
+}
+
+// Returns a copy of the nodes created so far, after checking that none of them has the given ID
+func (network *SmartContractAvalancheNetwork) getNodesSnapshot(newServiceId services.ServiceID) (map[services.ServiceID]*avalanchegonode.NodeAPIService, error) {
+	network.nodesMutex.Lock()
+	defer network.nodesMutex.Unlock()
+	if _, found := network.nodes[newServiceId]; found {
+		return nil, stacktrace.NewError("A node with ID '%v' already exists", newServiceId)
+	}
+	result := map[services.ServiceID]*avalanchegonode.NodeAPIService{}
+	for serviceId, node := range network.nodes {
+		result[serviceId] = node
 	}
-	logrus.Infof("C-Chain address '%v' created", cChainAddrStr)
+	return result, nil
+}
 
-	logrus.Info("Transferring balance to C-Chain address...")
-	cChainAddrBytes := common.HexToAddress(cChainAddrStr)
-	genesis := topo.Genesis()
-	genesis.MoveBalanceToCChain(cChainAddrBytes, testconstants.TxFee)
-	logrus.Info("Balance transferred to C-Chain address")
+// Creates a C-Chain account for each of the given names and funds it from the genesis allocation with the
+//  balance set in the network config, so that tests can act as several distinct parties (e.g. owner, user, attacker)
+// If the network config has a deterministic account key source, the same sequence of calls yields the same addresses on
+//  every run
+// The accounts are returned in the same order as the names
+// NOTE: The avalanchego API clients that the funding goes through take no context, so the context is only checked
+//  before funding starts
+func (network *SmartContractAvalancheNetwork) GetFundedAccounts(ctx context.Context, accountNames ...string) ([]*FundedAccount, error) {
+	if network.genesisFunder == nil {
+		return nil, stacktrace.NewError("Funded accounts can't be created until the Avalanche network has been set up")
+	}
 
-	logrus.Info("Creating keyed transactor...")
-	_, privKeyHexWithLead0x, err := firstNodeCChainApi.ExportKey(firstNode.UserPass, cChainAddrStr)
-	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred exporting the private key for the C-Chain address '%v'", cChainAddrStr)
+	accounts := []*FundedAccount{}
+	addresses := []common.Address{}
+	keyOrigins := []string{}
+	for _, name := range accountNames {
+		privKeyEcdsa, keyOrigin, err := network.accountKeyGenerator.nextKey()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting a private key for account '%v'", name)
+		}
+		account := newFundedAccount(name, privKeyEcdsa)
+		accounts = append(accounts, account)
+		addresses = append(addresses, account.Address)
+		keyOrigins = append(keyOrigins, keyOrigin)
 	}
-	privKeyHex := strings.Replace(privKeyHexWithLead0x, hexStrIndicatorLeader, "", 1)
-	logrus.Infof("C-Chain private key hex: %v", privKeyHex)
-	privKeyEcdsa, err := crypto.HexToECDSA(privKeyHex)
-	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred converting the C-Chain private key hex '%v' to an ECDSA key", privKeyHex)
+
+	if err := ctx.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "Gave up before funding accounts %v", accountNames)
+	}
+	logrus.Infof("Funding %v C-Chain accounts with %v nAVAX each...", len(accounts), network.fundedAccountBalance)
+	if err := network.genesisFunder.fundCChainAddresses(addresses, network.fundedAccountBalance); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred funding accounts %v from the genesis allocation", accountNames)
 	}
-	transactor := bind.NewKeyedTransactor(privKeyEcdsa)
-	logrus.Info("Keyed transactor created")
+	for i, account := range accounts {
+		logrus.Infof(
+			"Funded C-Chain account '%v' with address '%v' using %v key",
+			account.Name,
+			account.Address.Hex(),
+			keyOrigins[i])
+	}
+	return accounts, nil
+}
+
+// Wraps the funded account's transactor in a NonceManagingTransactor that uses the network's gas strategy
+// Only one should be created per account, else their nonces will collide
+func (network SmartContractAvalancheNetwork) NewAccountTransactor(account *FundedAccount) (*NonceManagingTransactor, error) {
+	if network.gethClient == nil {
+		return nil, stacktrace.NewError("Account transactors can't be created until the Avalanche network has been set up")
+	}
+	return NewNonceManagingTransactor(network.gethClient, account.Transactor, network.gasStrategy, network.gasUsageRecorder), nil
+}
 
+// Returns the recorder holding the gas used by every transaction sent through the network's NonceManagingTransactors
+//  and waited on with WaitForTransactionAccepted
+func (network SmartContractAvalancheNetwork) GetGasUsageRecorder() *GasUsageRecorder {
+	return network.gasUsageRecorder
+}
 
-	logrus.Info("Creating Geth client...")
-	uri := fmt.Sprintf("ws://%s:%d/ext/bc/C/ws", firstNode.GetIPAddress(), nodeHttpPort)
-	gethClient, err := ethclient.Dial(uri)
-	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred getting an ethclient for URI '%v'", uri)
+// Returns C-Chain clients for every node in the network, keyed by node ID, so that tests can e.g. send a transaction
+//  through one node and check that it's visible through another
+func (network SmartContractAvalancheNetwork) GetCChainClients() map[string]*NodeCChainClients {
+	result := map[string]*NodeCChainClients{}
+	for id, clients := range network.cChainClients {
+		result[id] = clients
 	}
-	logrus.Info("Geth client created")
+	return result
+}
 
-	network.transactor = transactor
-	network.gethClient = gethClient
+func (network SmartContractAvalancheNetwork) GetNodeCChainClients(nodeId string) (*NodeCChainClients, error) {
+	clients, found := network.cChainClients[nodeId]
+	if !found {
+		return nil, stacktrace.NewError("No C-Chain clients exist for node '%v'", nodeId)
+	}
+	return clients, nil
+}
 
-	return nil
+// Returns the addresses of every node in the network, keyed by node ID
+func (network SmartContractAvalancheNetwork) GetNodeEndpoints() map[string]NodeEndpoint {
+	result := map[string]NodeEndpoint{}
+	for id, endpoint := range network.nodeEndpoints {
+		result[id] = endpoint
+	}
+	return result
 }
 
-// Returns a Geth client for interacting with the Avalanche network's C-Chain, as well as a transactor required for making
-//  requests using the Geth client
-func (network SmartContractAvalancheNetwork) GetFundedCChainClientAndTransactor() (*ethclient.Client, *bind.TransactOpts) {
-	return network.gethClient, network.transactor
+// Closes the C-Chain clients of every node; the nodes themselves are cleaned up by Kurtosis
+func (network SmartContractAvalancheNetwork) Close() error {
+	for _, clients := range network.cChainClients {
+		clients.Websocket.Close()
+		clients.Http.Close()
+	}
+	return nil
 }
 
 func getBootstrapNodeId(idx int) string {
 	return fmt.Sprintf("bootstrapNode-%d", idx)
 }
 
+func getAdditionalNodeId(idx int) string {
+	return fmt.Sprintf("additionalNode-%d", idx)
+}
+
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+// Describes the shape of the Avalanche network that a smart contract test will run against
+type SmartContractAvalancheNetworkConfig struct {
+	AvalancheImage string
+
+	// Number of non-bootstrap nodes to add on top of the default bootstrap stakers
+	// These nodes follow the chain and serve the APIs like any other node, but they aren't validators, since registering
+	//  validators would take staking keys and P-Chain stake transactions that the network doesn't set up
+	NumAdditionalNodes int
+
+	// Consensus params for every node; a test can loosen or tighten them by editing its own copy of the config
+	Snow SnowParams
+
+	NodePorts NodePorts
+
+	NodeStartup NodeStartupPolicy
+
+	// Amount of nAVAX that each account returned by GetFundedAccounts gets
+	FundedAccountBalance uint64
+
+	// Where the keys for the transactor and all funded accounts come from; the transactor always gets the first key
+	AccountKeys AccountKeySource
+
+	// How the gas price and limit of transactions sent through the network's NonceManagingTransactors are chosen; a test
+	//  can override the suite-wide strategy by editing its own copy of the config
+	Gas GasStrategy
+
+	// If enabled, tests run against this existing node instead, and everything above that describes the shape of the
+	//  Avalanche network is ignored
+	ExternalRpc ExternalRpcConfig
+}
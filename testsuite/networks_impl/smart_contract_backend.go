@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/palantir/stacktrace"
+	"io"
+	"math/big"
+)
+
+// A C-Chain client that can be passed to the generated bindings and the contract registry, and can also read blocks,
+//  transactions, and account state; satisfied by both ethclient.Client and backends.SimulatedBackend
+type CChainClient interface {
+	bind.ContractBackend
+	ethereum.ChainReader
+	ethereum.TransactionReader
+
+	// The rest of ethereum.ChainStateReader, whose CodeAt overlaps with bind.ContractBackend's
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// Everything a smart contract test needs from the chain it runs against, so that the same test body can run on a full
+//  Avalanche network (SmartContractAvalancheNetwork), an existing node (ExternalRpcSmartContractBackend), or an
+//  in-memory chain in a plain 'go test' (SimulatedSmartContractBackend)
+type SmartContractBackend interface {
+	GetClient() CChainClient
+
+	GetChainId(ctx context.Context) (*big.Int, error)
+
+	// Transactor for an account that was funded when the backend was set up
+	GetNonceManagingTransactor() *NonceManagingTransactor
+
+	// Creates and funds a new account for each name, returning them in the same order as the names
+	GetFundedAccounts(ctx context.Context, accountNames ...string) ([]*FundedAccount, error)
+
+	// Wraps a funded account's transactor; only one should be created per account
+	NewAccountTransactor(account *FundedAccount) (*NonceManagingTransactor, error)
+
+	// Blocks until the transaction's effects are visible through every client, returning a *TransactionRevertedError
+	//  along with the receipt if it reverted
+	WaitForTransactionAccepted(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+
+	// Blocks until every client has seen at least the given block
+	WaitForBlockNumber(ctx context.Context, blockNumber uint64) error
+
+	// Returns an error if the clients disagree on any of the queried state
+	AssertCChainStateConsistent(ctx context.Context, query CChainStateQuery) error
+
+	GetGasUsageRecorder() *GasUsageRecorder
+}
+
+// Sets up the backend that the network config describes: a connection to the external node if one is configured, or
+//  else a new Avalanche network in the test's Kurtosis network; meant to be called from a test's Setup, which can then
+//  return the backend as its network
+func SetUpSmartContractBackend(
+		ctx context.Context,
+		config SmartContractAvalancheNetworkConfig,
+		networkCtx *networks.NetworkContext) (SmartContractBackend, error) {
+	if externalRpcConfig := config.ExternalRpc; externalRpcConfig.IsEnabled() {
+		fundedPrivateKey, err := externalRpcConfig.LoadFundedPrivateKey()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred loading the funded private key for the external RPC endpoint")
+		}
+		backend, err := NewExternalRpcSmartContractBackend(ctx, externalRpcConfig.RpcUrl, fundedPrivateKey, config)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred connecting to external RPC endpoint '%v'", externalRpcConfig.RpcUrl)
+		}
+		return backend, nil
+	}
+
+	network := NewSmartContractAvalancheNetwork(config, networkCtx)
+	if err := network.SetupAvalancheNetwork(ctx); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred setting up the Avalanche network")
+	}
+	return network, nil
+}
+
+// Closes the backend's C-Chain connections (or, for the simulated backend, its in-memory chain), if it has a Close
+//  method; meant to be deferred at the start of a test's Run, since Setup hands the backend over to it
+func CloseSmartContractBackend(backend SmartContractBackend) error {
+	closer, ok := backend.(io.Closer)
+	if !ok {
+		return nil
+	}
+	if err := closer.Close(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred closing the smart contract backend")
+	}
+	return nil
+}
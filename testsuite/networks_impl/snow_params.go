@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"fmt"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	defaultSnowSampleSize = 3
+	defaultSnowQuorumSize = 3
+
+	// Indicates that the node's own default should be used
+	useNodeDefault = 0
+
+	snowVirtuousCommitThresholdFlag = "--snow-virtuous-commit-threshold"
+	snowRogueCommitThresholdFlag    = "--snow-rogue-commit-threshold"
+	snowConcurrentRepollsFlag       = "--snow-concurrent-repolls"
+)
+
+// Snow consensus parameters that every node in the network is launched with
+type SnowParams struct {
+	SampleSize int `json:"sampleSize"`
+	QuorumSize int `json:"quorumSize"`
+
+	// Beta values; 0 means "use the node default"
+	VirtuousCommitThreshold int `json:"virtuousCommitThreshold"`
+	RogueCommitThreshold    int `json:"rogueCommitThreshold"`
+
+	// 0 means "use the node default"
+	ConcurrentRepolls int `json:"concurrentRepolls"`
+}
+
+func NewDefaultSnowParams() SnowParams {
+	return SnowParams{
+		SampleSize:              defaultSnowSampleSize,
+		QuorumSize:              defaultSnowQuorumSize,
+		VirtuousCommitThreshold: useNodeDefault,
+		RogueCommitThreshold:    useNodeDefault,
+		ConcurrentRepolls:       useNodeDefault,
+	}
+}
+
+// Checks the params against the same constraints that AvalancheGo enforces, so that bad values are reported before
+//  any nodes get launched
+func (params SnowParams) Validate() error {
+	if params.SampleSize < 1 {
+		return stacktrace.NewError("Snow sample size must be >= 1, but was %v", params.SampleSize)
+	}
+	if params.QuorumSize <= params.SampleSize / 2 || params.QuorumSize > params.SampleSize {
+		return stacktrace.NewError(
+			"Snow quorum size must be > half the sample size and <= the sample size of %v, but was %v",
+			params.SampleSize,
+			params.QuorumSize)
+	}
+	if params.VirtuousCommitThreshold < 0 || params.RogueCommitThreshold < 0 || params.ConcurrentRepolls < 0 {
+		return stacktrace.NewError(
+			"Snow virtuous commit threshold, rogue commit threshold, and concurrent repolls must be >= 0, but were %v, %v, and %v",
+			params.VirtuousCommitThreshold,
+			params.RogueCommitThreshold,
+			params.ConcurrentRepolls)
+	}
+
+	// The node's defaults aren't known here, so values left at the default can only be checked by the node itself
+	if params.VirtuousCommitThreshold != useNodeDefault &&
+		params.RogueCommitThreshold != useNodeDefault &&
+		params.RogueCommitThreshold < params.VirtuousCommitThreshold {
+		return stacktrace.NewError(
+			"Snow rogue commit threshold must be >= the virtuous commit threshold of %v, but was %v",
+			params.VirtuousCommitThreshold,
+			params.RogueCommitThreshold)
+	}
+	if params.ConcurrentRepolls != useNodeDefault &&
+		params.RogueCommitThreshold != useNodeDefault &&
+		params.ConcurrentRepolls > params.RogueCommitThreshold {
+		return stacktrace.NewError(
+			"Snow concurrent repolls must be <= the rogue commit threshold of %v, but was %v",
+			params.RogueCommitThreshold,
+			params.ConcurrentRepolls)
+	}
+	return nil
+}
+
+// Returns the node flags for the params that the avalanchego-kurtosis network builder has no setting for; params left
+//  at the node default get no flag
+func (params SnowParams) getAdditionalNodeFlags() []string {
+	result := []string{}
+	if params.VirtuousCommitThreshold != useNodeDefault {
+		result = append(result, fmt.Sprintf("%v=%d", snowVirtuousCommitThresholdFlag, params.VirtuousCommitThreshold))
+	}
+	if params.RogueCommitThreshold != useNodeDefault {
+		result = append(result, fmt.Sprintf("%v=%d", snowRogueCommitThresholdFlag, params.RogueCommitThreshold))
+	}
+	if params.ConcurrentRepolls != useNodeDefault {
+		result = append(result, fmt.Sprintf("%v=%d", snowConcurrentRepollsFlag, params.ConcurrentRepolls))
+	}
+	return result
+}
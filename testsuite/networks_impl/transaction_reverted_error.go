@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/palantir/stacktrace"
+	"math/big"
+)
+
+const (
+	selectorLenBytes = 4
+
+	unknownRevertReason = "<no revert reason could be determined>"
+)
+
+var (
+	// Selector of the Error(string) payload that require() and revert("...") produce
+	errorStringSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+	// Selector of the Panic(uint256) payload that Solidity >= 0.8 produces for failed asserts, overflows, etc.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+	// Source: https://docs.soliditylang.org/en/v0.8.0/control-structures.html#panic-via-assert-and-error-via-require
+	panicCodeDescriptions = map[uint64]string{
+		0x01: "assertion failed",
+		0x11: "arithmetic overflow or underflow",
+		0x12: "division or modulo by zero",
+		0x21: "conversion to an invalid enum value",
+		0x22: "incorrectly encoded storage byte array",
+		0x31: "pop() on an empty array",
+		0x32: "array index out of bounds",
+		0x41: "too much memory allocated",
+		0x51: "call to a zero-initialized internal function",
+	}
+)
+
+// Returned when a transaction was included in a block but reverted (i.e. has receipt status 0)
+// This is returned without wrapping, so callers expecting a revert can check for it with stacktrace.RootCause
+type TransactionRevertedError struct {
+	Receipt *types.Receipt
+
+	// Decoded from the Error(string) or Panic(uint256) payload, if there was one
+	Reason string
+}
+
+func (err *TransactionRevertedError) Error() string {
+	return fmt.Sprintf(
+		"Transaction '%v' reverted in block %v: %v",
+		err.Receipt.TxHash.Hex(),
+		err.Receipt.BlockNumber,
+		err.Reason)
+}
+
+// Receipts don't contain the revert reason, so we replay the transaction as a call from its sender against the given
+//  block's state (ideally the state it executed on top of) and decode the reason from the revert payload the node hands
+//  back; if the reason can't be determined, the error says so rather than failing
+// NOTE: If an earlier transaction in the same block changed the state the transaction depended on, the reason may differ
+//  from (or be missing compared to) the original
+func newTransactionRevertedError(
+		ctx context.Context,
+		client ethereum.ContractCaller,
+		receipt *types.Receipt,
+		tx *types.Transaction,
+		sender common.Address,
+		replayBlockNumber *big.Int) *TransactionRevertedError {
+	callMsg := ethereum.CallMsg{
+		From:     sender,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	returnData, callErr := client.CallContract(ctx, callMsg, replayBlockNumber)
+
+	if callErr == nil {
+		// Older nodes return the revert payload as the call result, rather than as error data
+		if hasKnownRevertSelector(returnData) {
+			return &TransactionRevertedError{Receipt: receipt, Reason: decodeRevertReason(returnData)}
+		}
+		return &TransactionRevertedError{Receipt: receipt, Reason: unknownRevertReason}
+	}
+
+	return &TransactionRevertedError{Receipt: receipt, Reason: getRevertReasonFromCallError(callErr)}
+}
+
+// Returns the revert reason of a failed contract call (e.g. from the generated bindings or DynamicContract.Call), in
+//  the same format as TransactionRevertedError.Reason; errors that aren't reverts return their message
+func GetCallRevertReason(err error) string {
+	return getRevertReasonFromCallError(stacktrace.RootCause(err))
+}
+
+func getRevertReasonFromCallError(callErr error) string {
+	// Newer nodes return the revert payload as the error's data
+	dataErr, ok := callErr.(rpc.DataError)
+	if !ok {
+		return callErr.Error()
+	}
+	revertDataHex, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return callErr.Error()
+	}
+	revertData, err := hexutil.Decode(revertDataHex)
+	if err != nil {
+		return callErr.Error()
+	}
+	return decodeRevertReason(revertData)
+}
+
+func hasKnownRevertSelector(data []byte) bool {
+	if len(data) < selectorLenBytes {
+		return false
+	}
+	selector := data[:selectorLenBytes]
+	return bytes.Equal(selector, errorStringSelector) || bytes.Equal(selector, panicSelector)
+}
+
+// Turns an Error(string) or Panic(uint256) revert payload into a human-readable reason
+func decodeRevertReason(revertData []byte) string {
+	if len(revertData) < selectorLenBytes {
+		return unknownRevertReason
+	}
+	selector := revertData[:selectorLenBytes]
+	switch {
+	case bytes.Equal(selector, errorStringSelector):
+		reason, err := abi.UnpackRevert(revertData)
+		if err != nil {
+			return fmt.Sprintf("Error(string) with undecodable payload '%v'", hexutil.Encode(revertData))
+		}
+		return fmt.Sprintf("Error(%q)", reason)
+	case bytes.Equal(selector, panicSelector):
+		payload := revertData[selectorLenBytes:]
+		if len(payload) != common.HashLength {
+			return fmt.Sprintf("Panic(uint256) with undecodable payload '%v'", hexutil.Encode(revertData))
+		}
+		code := new(big.Int).SetBytes(payload)
+		description, found := panicCodeDescriptions[code.Uint64()]
+		if !code.IsUint64() || !found {
+			description = "unknown panic code"
+		}
+		return fmt.Sprintf("Panic(0x%x): %v", code, description)
+	default:
+		return fmt.Sprintf("unrecognized revert payload '%v'", hexutil.Encode(revertData))
+	}
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+	"time"
+)
+
+const (
+	// How often to check a wait condition when the client can't subscribe to new heads (e.g. over HTTP)
+	newHeadPollInterval = 1 * time.Second
+)
+
+// What the wait helpers need from a client; satisfied by both ethclient.Client and backends.SimulatedBackend
+type waitableClient interface {
+	ethereum.ChainReader
+	ethereum.TransactionReader
+}
+
+// Blocks until the transaction has been included in a block and every node in the network has accepted that block, so
+//  that the transaction's effects are visible through any of their clients
+// Rather than polling, this waits on new-head subscriptions, so it returns as soon as the state is visible; the only
+//  limit on how long it waits is the context's deadline
+// If the transaction reverted, the receipt is returned along with a *TransactionRevertedError containing the decoded
+//  revert reason
+// Transactions sent through the network's NonceManagingTransactors get their gas usage recorded here
+func (network SmartContractAvalancheNetwork) WaitForTransactionAccepted(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	nodeIds := []string{}
+	for id := range network.cChainClients {
+		nodeIds = append(nodeIds, id)
+	}
+	sort.Strings(nodeIds)
+	return network.WaitForTransactionAcceptedByNodes(ctx, txHash, nodeIds...)
+}
+
+// Like WaitForTransactionAccepted, but only waits for the given nodes to accept the transaction's block
+func (network SmartContractAvalancheNetwork) WaitForTransactionAcceptedByNodes(ctx context.Context, txHash common.Hash, nodeIds ...string) (*types.Receipt, error) {
+	if network.gethClient == nil {
+		return nil, stacktrace.NewError("Can't wait for transactions until the Avalanche network has been set up")
+	}
+
+	receipt, err := waitForReceipt(ctx, network.gethClient, txHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred waiting for transaction '%v' to be included in a block", txHash.Hex())
+	}
+	logrus.Debugf("Transaction '%v' included in block %v", txHash.Hex(), receipt.BlockNumber)
+
+	for _, id := range nodeIds {
+		clients, found := network.cChainClients[id]
+		if !found {
+			return nil, stacktrace.NewError("Can't wait for transaction '%v' on node '%v' because no such node exists", txHash.Hex(), id)
+		}
+		if err := waitForReceiptInBlock(ctx, clients.Websocket, txHash, receipt.BlockHash); err != nil {
+			return nil, stacktrace.Propagate(
+				err,
+				"An error occurred waiting for node '%v' to accept block %v containing transaction '%v'",
+				id,
+				receipt.BlockNumber,
+				txHash.Hex())
+		}
+	}
+	logrus.Debugf("Block %v containing transaction '%v' accepted by nodes %v", receipt.BlockNumber, txHash.Hex(), nodeIds)
+	network.gasUsageRecorder.recordReceipt(receipt)
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		tx, _, err := network.gethClient.TransactionByHash(ctx, receipt.TxHash)
+		if err != nil {
+			return receipt, stacktrace.Propagate(err, "Transaction '%v' reverted, and an error occurred getting it to determine the revert reason", txHash.Hex())
+		}
+		sender, err := network.gethClient.TransactionSender(ctx, tx, receipt.BlockHash, receipt.TransactionIndex)
+		if err != nil {
+			return receipt, stacktrace.Propagate(err, "Transaction '%v' reverted, and an error occurred getting its sender to determine the revert reason", txHash.Hex())
+		}
+		// The transaction is replayed against the end state of the previous block, which is the state it executed on top of
+		replayBlockNumber := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+		return receipt, newTransactionRevertedError(ctx, network.gethClient, receipt, tx, sender, replayBlockNumber)
+	}
+	return receipt, nil
+}
+
+// Waits until the client returns a receipt for the transaction
+func waitForReceipt(ctx context.Context, client waitableClient, txHash common.Hash) (*types.Receipt, error) {
+	var receipt *types.Receipt
+	isDone := func() (bool, error) {
+		candidate, err := client.TransactionReceipt(ctx, txHash)
+		if err == ethereum.NotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, stacktrace.Propagate(err, "An error occurred getting the receipt for transaction '%v'", txHash.Hex())
+		}
+		// SimulatedBackend returns a nil receipt rather than NotFound for transactions it hasn't mined
+		if candidate == nil || candidate.BlockNumber == nil {
+			return false, nil
+		}
+		receipt = candidate
+		return true, nil
+	}
+	if err := waitForNewHeadCondition(ctx, client, isDone); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Waits until the client has a receipt for the transaction in the block with the given hash, meaning the client has
+//  accepted that block
+// This compares the hash the node reports rather than hashing the node's header locally, because go-ethereum's header
+//  type lacks the C-Chain's extra header fields and so hashes C-Chain headers to the wrong value
+func waitForReceiptInBlock(ctx context.Context, client waitableClient, txHash common.Hash, blockHash common.Hash) error {
+	isDone := func() (bool, error) {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == ethereum.NotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, stacktrace.Propagate(err, "An error occurred getting the receipt for transaction '%v'", txHash.Hex())
+		}
+		if receipt == nil {
+			return false, nil
+		}
+		return receipt.BlockHash == blockHash, nil
+	}
+	return waitForNewHeadCondition(ctx, client, isDone)
+}
+
+// Waits until every node in the network has accepted at least the given block
+func (network SmartContractAvalancheNetwork) WaitForBlockNumber(ctx context.Context, blockNumber uint64) error {
+	if network.gethClient == nil {
+		return stacktrace.NewError("Can't wait for blocks until the Avalanche network has been set up")
+	}
+	nodeIds := []string{}
+	for id := range network.cChainClients {
+		nodeIds = append(nodeIds, id)
+	}
+	sort.Strings(nodeIds)
+	for _, id := range nodeIds {
+		if err := waitForBlockNumber(ctx, network.cChainClients[id].Websocket, blockNumber); err != nil {
+			return stacktrace.Propagate(err, "An error occurred waiting for node '%v' to accept block %v", id, blockNumber)
+		}
+	}
+	return nil
+}
+
+// Waits until the client's latest block is at least the given block
+func waitForBlockNumber(ctx context.Context, client waitableClient, blockNumber uint64) error {
+	isDone := func() (bool, error) {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return false, stacktrace.Propagate(err, "An error occurred getting the latest header")
+		}
+		return header.Number.Uint64() >= blockNumber, nil
+	}
+	return waitForNewHeadCondition(ctx, client, isDone)
+}
+
+// Looks up the reverted transaction's sender and replays the transaction to build a *TransactionRevertedError, for
+//  clients that can't look senders up themselves
+func getTransactionRevertedError(
+		ctx context.Context,
+		client revertReplayClient,
+		signer types.Signer,
+		receipt *types.Receipt,
+		replayBlockNumber *big.Int) error {
+	tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return stacktrace.Propagate(err, "Transaction '%v' reverted, and an error occurred getting it to determine the revert reason", receipt.TxHash.Hex())
+	}
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return stacktrace.Propagate(err, "Transaction '%v' reverted, and an error occurred getting its sender to determine the revert reason", receipt.TxHash.Hex())
+	}
+	return newTransactionRevertedError(ctx, client, receipt, tx, sender, replayBlockNumber)
+}
+
+// What getTransactionRevertedError needs from a client
+type revertReplayClient interface {
+	ethereum.TransactionReader
+	ethereum.ContractCaller
+}
+
+// Checks the condition immediately and then every time the client sees a new head, until the condition is met, the
+//  condition fails, or the context is done
+// Clients that don't support subscriptions get polled instead
+func waitForNewHeadCondition(ctx context.Context, client waitableClient, isDone func() (bool, error)) error {
+	// The subscription must exist before the first check, else a head arriving between the check and the subscription
+	//  would be missed
+	newHeads := make(chan *types.Header)
+	subscription, err := client.SubscribeNewHead(ctx, newHeads)
+	if err == rpc.ErrNotificationsUnsupported {
+		return pollCondition(ctx, isDone)
+	}
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred subscribing to new heads")
+	}
+	// Once the context's deadline has passed, unsubscribing can deadlock go-ethereum's RPC client: a call whose write ran
+	//  into the deadline makes the client drop the connection, and the client ending the subscription because of that
+	//  races with the unsubscribe request; so the subscription is left to end when the client is closed instead
+	// The deadline is checked directly because the context only reports that it's done a moment after the deadline
+	defer func() {
+		deadline, hasDeadline := ctx.Deadline()
+		if ctx.Err() == nil && (!hasDeadline || time.Now().Before(deadline)) {
+			subscription.Unsubscribe()
+		}
+	}()
+
+	for {
+		done, err := isDone()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-newHeads:
+		case err := <-subscription.Err():
+			return stacktrace.Propagate(err, "The new heads subscription failed")
+		case <-ctx.Done():
+			return stacktrace.Propagate(ctx.Err(), "Gave up waiting for the condition to be met")
+		}
+	}
+}
+
+// Checks the condition immediately and then at a fixed interval, until the condition is met, the condition fails, or
+//  the context is done
+func pollCondition(ctx context.Context, isDone func() (bool, error)) error {
+	ticker := time.NewTicker(newHeadPollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := isDone()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return stacktrace.Propagate(ctx.Err(), "Gave up waiting for the condition to be met")
+		}
+	}
+}
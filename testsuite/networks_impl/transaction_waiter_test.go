@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package networks_impl
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"testing"
+	"time"
+)
+
+const (
+	waiterTestTimeout = 5 * time.Second
+
+	// How long to wait for a node that's never going to accept the transaction's block
+	waiterTestGiveUpTimeout = 200 * time.Millisecond
+
+	// How long the lagging node takes to accept the transaction's block
+	laggingNodeDelay = 50 * time.Millisecond
+)
+
+var (
+	waiterTestTxHash         = common.HexToHash("0x01")
+	waiterTestBlockHash      = common.HexToHash("0xb1")
+	waiterTestOtherBlockHash = common.HexToHash("0xb2")
+)
+
+// The block hashes the fake nodes report aren't what go-ethereum would compute from their headers, so this only passes
+//  if nodes are checked by the hash they report
+func TestWaitForTransactionAcceptedWaitsForEveryNode(t *testing.T) {
+	firstNode := newFakeCChainNode()
+	firstNode.setReceipt(newFakeReceipt(waiterTestTxHash, waiterTestBlockHash, 1))
+	laggingNode := newFakeCChainNode()
+	network := newFakeNodesTestNetwork(t, map[string]*fakeCChainNode{"first": firstNode, "lagging": laggingNode})
+	defer network.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), waiterTestTimeout)
+	defer cancelFunc()
+
+	time.AfterFunc(laggingNodeDelay, func() {
+		laggingNode.setReceipt(newFakeReceipt(waiterTestTxHash, waiterTestBlockHash, 1))
+	})
+	receipt, err := network.WaitForTransactionAccepted(ctx, waiterTestTxHash)
+	if err != nil {
+		t.Fatalf("An error occurred waiting for the transaction to be accepted: %v", err)
+	}
+	if receipt.BlockHash != waiterTestBlockHash {
+		t.Errorf("Expected the receipt to be for block '%v', but was for block '%v'", waiterTestBlockHash.Hex(), receipt.BlockHash.Hex())
+	}
+}
+
+// A node that put the transaction in a different block hasn't accepted the block the transaction was included in
+func TestWaitForTransactionAcceptedByNodesRejectsOtherBlocks(t *testing.T) {
+	firstNode := newFakeCChainNode()
+	firstNode.setReceipt(newFakeReceipt(waiterTestTxHash, waiterTestBlockHash, 1))
+	forkedNode := newFakeCChainNode()
+	forkedNode.setReceipt(newFakeReceipt(waiterTestTxHash, waiterTestOtherBlockHash, 1))
+	network := newFakeNodesTestNetwork(t, map[string]*fakeCChainNode{"first": firstNode, "forked": forkedNode})
+	defer network.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), waiterTestGiveUpTimeout)
+	defer cancelFunc()
+
+	if _, err := network.WaitForTransactionAcceptedByNodes(ctx, waiterTestTxHash, "first"); err != nil {
+		t.Fatalf("An error occurred waiting for the transaction to be accepted by the first node: %v", err)
+	}
+	if _, err := network.WaitForTransactionAcceptedByNodes(ctx, waiterTestTxHash, "forked"); err == nil {
+		t.Fatalf("Expected an error waiting for a node that put the transaction in a different block, but got none")
+	}
+}
+
+func TestWaitForTransactionAcceptedByNodesRejectsUnknownNodes(t *testing.T) {
+	firstNode := newFakeCChainNode()
+	firstNode.setReceipt(newFakeReceipt(waiterTestTxHash, waiterTestBlockHash, 1))
+	network := newFakeNodesTestNetwork(t, map[string]*fakeCChainNode{"first": firstNode})
+	defer network.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), waiterTestTimeout)
+	defer cancelFunc()
+
+	if _, err := network.WaitForTransactionAcceptedByNodes(ctx, waiterTestTxHash, "missing"); err == nil {
+		t.Fatalf("Expected an error waiting on a node that doesn't exist, but got none")
+	}
+}
+
+// Connects a network to the fake nodes as though it had been set up, with the alphabetically-first node as the node
+//  that transactions are sent to
+func newFakeNodesTestNetwork(t *testing.T, nodes map[string]*fakeCChainNode) *SmartContractAvalancheNetwork {
+	network := NewSmartContractAvalancheNetwork(SmartContractAvalancheNetworkConfig{}, nil)
+	firstNodeId := ""
+	for id, node := range nodes {
+		network.cChainClients[id] = node.dial(t)
+		if firstNodeId == "" || id < firstNodeId {
+			firstNodeId = id
+		}
+	}
+	network.gethClient = network.cChainClients[firstNodeId].Websocket
+	return network
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package test_catalog
+
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/palantir/stacktrace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Used for tests whose timeouts are set neither by their metadata nor by the timeout config
+	defaultSetupTimeout = 180 * time.Second
+	defaultRunTimeout   = 180 * time.Second
+
+	// Tests' own deadlines should expire this long before their Kurtosis timeouts do, so that a hung call fails the test
+	//  with an error saying what was hung rather than the test getting killed
+	TimeoutBuffer = 10 * time.Second
+)
+
+var (
+	registryMutex   sync.Mutex
+	registeredTests = map[string]RegisteredTest{}
+)
+
+// Creates a test from the suite-wide dependencies, adjusted for the test's metadata
+type TestConstructor func(dependencies TestDependencies) testsuite.Test
+
+// Describes a test to the suite, so that it can be given the network, timeouts, etc. it needs
+type TestMetadata struct {
+	// Free-form labels, e.g. "smoke" or "slow"
+	Tags []string
+
+	// Left at zero, these get the timeout config's defaults; either way, they can be overridden from the custom params
+	SetupTimeout time.Duration
+	RunTimeout   time.Duration
+
+	// The test's network gets at least this many nodes on top of the default bootstrap stakers, even if the suite-wide
+	//  network config asks for fewer
+	MinNumAdditionalNodes int
+}
+
+// What each test is constructed with; the network config is the test's own copy, so tests can adjust it freely
+type TestDependencies struct {
+	NetworkConfig networks_impl.SmartContractAvalancheNetworkConfig
+
+	GasReportConfig networks_impl.GasReportConfig
+
+	ContractRegistry *contract_registry.ContractRegistry
+
+	// The test should configure Kurtosis with these, and derive its own deadlines from them
+	SetupTimeout time.Duration
+	RunTimeout   time.Duration
+}
+
+type RegisteredTest struct {
+	Name string
+
+	Metadata TestMetadata
+
+	constructor TestConstructor
+}
+
+// Adds a test to the catalog; meant to be called from the init function of the test's package, which the suite then
+//  imports for its side effects
+// Like database/sql.Register, this panics on an invalid or duplicate registration, since that's a programming error
+//  that should fail the suite as soon as it starts
+func Register(name string, metadata TestMetadata, constructor TestConstructor) {
+	if err := TryRegister(name, metadata, constructor); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Like Register, but returns an error rather than panicking, for tests registered from data loaded at runtime (e.g.
+//  scenario files)
+func TryRegister(name string, metadata TestMetadata, constructor TestConstructor) error {
+	if err := validateRegistration(name, metadata, constructor); err != nil {
+		return stacktrace.Propagate(err, "Invalid registration for test '%v'", name)
+	}
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if _, found := registeredTests[name]; found {
+		return stacktrace.NewError("A test named '%v' is already registered", name)
+	}
+	registeredTests[name] = RegisteredTest{
+		Name:        name,
+		Metadata:    metadata,
+		constructor: constructor,
+	}
+	return nil
+}
+
+// Returns every registered test, sorted by name
+func GetRegisteredTests() []RegisteredTest {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	result := []RegisteredTest{}
+	for _, test := range registeredTests {
+		result = append(result, test)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Constructs the test, giving it its own copy of the suite-wide dependencies adjusted for its metadata, with timeouts
+//  from the timeout config
+func (test RegisteredTest) Build(suiteDependencies TestDependencies, timeoutConfig TimeoutConfig) testsuite.Test {
+	dependencies := suiteDependencies
+	if dependencies.NetworkConfig.NumAdditionalNodes < test.Metadata.MinNumAdditionalNodes {
+		dependencies.NetworkConfig.NumAdditionalNodes = test.Metadata.MinNumAdditionalNodes
+	}
+	// Done after the node count is adjusted, since the setup timeout scales with them
+	dependencies.SetupTimeout, dependencies.RunTimeout = timeoutConfig.getTimeouts(test, dependencies)
+	return test.constructor(dependencies)
+}
+
+func (test RegisteredTest) hasAnyTag(tags map[string]bool) bool {
+	for _, testTag := range test.Metadata.Tags {
+		if tags[testTag] {
+			return true
+		}
+	}
+	return false
+}
+
+func validateRegistration(name string, metadata TestMetadata, constructor TestConstructor) error {
+	if strings.TrimSpace(name) == "" {
+		return stacktrace.NewError("Test name is empty")
+	}
+	if constructor == nil {
+		return stacktrace.NewError("Test constructor is nil")
+	}
+	for _, tag := range metadata.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return stacktrace.NewError("Test tags may not be empty")
+		}
+	}
+	if metadata.SetupTimeout != 0 {
+		if err := validateTimeout(metadata.SetupTimeout); err != nil {
+			return stacktrace.Propagate(err, "Invalid setup timeout")
+		}
+	}
+	if metadata.RunTimeout != 0 {
+		if err := validateTimeout(metadata.RunTimeout); err != nil {
+			return stacktrace.Propagate(err, "Invalid run timeout")
+		}
+	}
+	if metadata.MinNumAdditionalNodes < 0 {
+		return stacktrace.NewError("The minimum number of additional nodes must be >= 0, but was %v", metadata.MinNumAdditionalNodes)
+	}
+	return nil
+}
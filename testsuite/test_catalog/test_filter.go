@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package test_catalog
+
+import (
+	"github.com/palantir/stacktrace"
+	"regexp"
+	"strings"
+)
+
+// Selects which of the registered tests the suite runs, e.g. a fast smoke subset on every commit and everything
+//  nightly; a test is selected if it passes every filter that's set, so the zero value selects every test
+// Name patterns are unanchored Go regexes, so use e.g. '^smartContractTest$' to match a single test exactly
+type TestFilterConfig struct {
+	// If set, a test's name must match at least one of these
+	IncludeNamePatterns []string	`json:"includeNamePatterns"`
+
+	// A test whose name matches any of these is skipped
+	ExcludeNamePatterns []string	`json:"excludeNamePatterns"`
+
+	// If set, a test must have at least one of these tags
+	IncludeTags []string	`json:"includeTags"`
+
+	// A test with any of these tags is skipped, even if it has one of the included tags
+	ExcludeTags []string	`json:"excludeTags"`
+}
+
+// A TestFilterConfig with its patterns compiled
+type TestFilter struct {
+	includeNameRegexes []*regexp.Regexp
+	excludeNameRegexes []*regexp.Regexp
+	includeTags        map[string]bool
+	excludeTags        map[string]bool
+}
+
+func NewTestFilter(config TestFilterConfig) (*TestFilter, error) {
+	includeNameRegexes, err := compileNamePatterns(config.IncludeNamePatterns)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid include name pattern")
+	}
+	excludeNameRegexes, err := compileNamePatterns(config.ExcludeNamePatterns)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid exclude name pattern")
+	}
+	includeTags, err := toTagSet(config.IncludeTags)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid include tag")
+	}
+	excludeTags, err := toTagSet(config.ExcludeTags)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid exclude tag")
+	}
+	return &TestFilter{
+		includeNameRegexes: includeNameRegexes,
+		excludeNameRegexes: excludeNameRegexes,
+		includeTags:        includeTags,
+		excludeTags:        excludeTags,
+	}, nil
+}
+
+func (filter TestFilter) Matches(test RegisteredTest) bool {
+	if len(filter.includeNameRegexes) > 0 && !matchesAny(filter.includeNameRegexes, test.Name) {
+		return false
+	}
+	if matchesAny(filter.excludeNameRegexes, test.Name) {
+		return false
+	}
+	if len(filter.includeTags) > 0 && !test.hasAnyTag(filter.includeTags) {
+		return false
+	}
+	if test.hasAnyTag(filter.excludeTags) {
+		return false
+	}
+	return true
+}
+
+func compileNamePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	result := []*regexp.Regexp{}
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "'%v' isn't a valid regex", pattern)
+		}
+		result = append(result, regex)
+	}
+	return result, nil
+}
+
+func toTagSet(tags []string) (map[string]bool, error) {
+	result := map[string]bool{}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return nil, stacktrace.NewError("Tags may not be empty")
+		}
+		result[tag] = true
+	}
+	return result, nil
+}
+
+func matchesAny(regexes []*regexp.Regexp, str string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(str) {
+			return true
+		}
+	}
+	return false
+}
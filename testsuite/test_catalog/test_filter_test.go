@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package test_catalog_test
+
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
+	"testing"
+)
+
+// Stand-ins for registered tests; the filter only looks at names and tags
+var filterTestCatalog = []test_catalog.RegisteredTest{
+	newFilterTestCatalogEntry("smartContractTest", "smoke", "bindings"),
+	newFilterTestCatalogEntry("helloWorldScenario", "scenario", "smoke"),
+	newFilterTestCatalogEntry("simpleStorageScenario", "scenario", "slow"),
+	newFilterTestCatalogEntry("untaggedTest"),
+}
+
+func TestFilterMatches(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		config test_catalog.TestFilterConfig
+
+		expectedMatchingTests []string
+	}{
+		{
+			name:                  "Zero value selects every test",
+			config:                test_catalog.TestFilterConfig{},
+			expectedMatchingTests: []string{"smartContractTest", "helloWorldScenario", "simpleStorageScenario", "untaggedTest"},
+		},
+		{
+			name: "Include name pattern is unanchored",
+			config: test_catalog.TestFilterConfig{
+				IncludeNamePatterns: []string{"Scenario"},
+			},
+			expectedMatchingTests: []string{"helloWorldScenario", "simpleStorageScenario"},
+		},
+		{
+			name: "Any include name pattern may match",
+			config: test_catalog.TestFilterConfig{
+				IncludeNamePatterns: []string{"^smartContractTest$", "^untagged"},
+			},
+			expectedMatchingTests: []string{"smartContractTest", "untaggedTest"},
+		},
+		{
+			name: "Exclude name pattern",
+			config: test_catalog.TestFilterConfig{
+				ExcludeNamePatterns: []string{"^simple", "Test$"},
+			},
+			expectedMatchingTests: []string{"helloWorldScenario"},
+		},
+		{
+			name: "Include tag",
+			config: test_catalog.TestFilterConfig{
+				IncludeTags: []string{"smoke"},
+			},
+			expectedMatchingTests: []string{"smartContractTest", "helloWorldScenario"},
+		},
+		{
+			name: "Any include tag may match",
+			config: test_catalog.TestFilterConfig{
+				IncludeTags: []string{"bindings", "slow"},
+			},
+			expectedMatchingTests: []string{"smartContractTest", "simpleStorageScenario"},
+		},
+		{
+			name: "Exclude tag",
+			config: test_catalog.TestFilterConfig{
+				ExcludeTags: []string{"scenario"},
+			},
+			expectedMatchingTests: []string{"smartContractTest", "untaggedTest"},
+		},
+		{
+			name: "Exclude tag wins over include tag",
+			config: test_catalog.TestFilterConfig{
+				IncludeTags: []string{"smoke"},
+				ExcludeTags: []string{"scenario"},
+			},
+			expectedMatchingTests: []string{"smartContractTest"},
+		},
+		{
+			name: "Exclude name pattern wins over include name pattern",
+			config: test_catalog.TestFilterConfig{
+				IncludeNamePatterns: []string{"Scenario$"},
+				ExcludeNamePatterns: []string{"^hello"},
+			},
+			expectedMatchingTests: []string{"simpleStorageScenario"},
+		},
+		{
+			name: "Name and tag filters must all pass",
+			config: test_catalog.TestFilterConfig{
+				IncludeNamePatterns: []string{"Scenario$"},
+				IncludeTags:         []string{"smoke"},
+			},
+			expectedMatchingTests: []string{"helloWorldScenario"},
+		},
+		{
+			name: "Nothing matches",
+			config: test_catalog.TestFilterConfig{
+				IncludeTags: []string{"nightly"},
+			},
+			expectedMatchingTests: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		filter, err := test_catalog.NewTestFilter(testCase.config)
+		if err != nil {
+			t.Errorf("%v: an error occurred creating the filter: %v", testCase.name, err)
+			continue
+		}
+		expectedMatches := map[string]bool{}
+		for _, testName := range testCase.expectedMatchingTests {
+			expectedMatches[testName] = true
+		}
+		for _, test := range filterTestCatalog {
+			if isMatch := filter.Matches(test); isMatch != expectedMatches[test.Name] {
+				t.Errorf("%v: expected test '%v' to match to be %v, but was %v", testCase.name, test.Name, expectedMatches[test.Name], isMatch)
+			}
+		}
+	}
+}
+
+func TestNewTestFilterRejectsInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		config test_catalog.TestFilterConfig
+	}{
+		{
+			name:   "Invalid include name pattern",
+			config: test_catalog.TestFilterConfig{IncludeNamePatterns: []string{"smart(Contract"}},
+		},
+		{
+			name:   "Invalid exclude name pattern",
+			config: test_catalog.TestFilterConfig{ExcludeNamePatterns: []string{"*Scenario"}},
+		},
+		{
+			name:   "Empty include tag",
+			config: test_catalog.TestFilterConfig{IncludeTags: []string{"smoke", ""}},
+		},
+		{
+			name:   "Blank exclude tag",
+			config: test_catalog.TestFilterConfig{ExcludeTags: []string{" "}},
+		},
+	}
+	for _, testCase := range testCases {
+		if _, err := test_catalog.NewTestFilter(testCase.config); err == nil {
+			t.Errorf("%v: expected an error creating the filter, but got none", testCase.name)
+		}
+	}
+}
+
+func newFilterTestCatalogEntry(name string, tags ...string) test_catalog.RegisteredTest {
+	return test_catalog.RegisteredTest{
+		Name: name,
+		Metadata: test_catalog.TestMetadata{
+			Tags: tags,
+		},
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package test_catalog
+
+import (
+	"github.com/palantir/stacktrace"
+	"time"
+)
+
+const (
+	// Each node past the bootstrap stakers (which the base setup timeout already covers) adds roughly this much to the
+	//  network's setup: they're launched concurrently, but Kurtosis starts their containers one at a time
+	defaultSetupTimeoutPerAdditionalNodeSeconds = 15
+)
+
+// Timeouts from the custom params, so that e.g. slow CI hosts can get longer timeouts without code changes
+// Each test's base timeouts come from, in order of precedence: its entry in PerTest, its own metadata, then the
+//  defaults here; the setup timeout then grows by SetupTimeoutPerAdditionalNodeSeconds for every node the test's
+//  network has on top of the bootstrap stakers, since bigger networks take longer to start
+type TimeoutConfig struct {
+	// Left at zero, these use the catalog's built-in defaults
+	DefaultSetupTimeoutSeconds int	`json:"defaultSetupTimeoutSeconds"`
+	DefaultRunTimeoutSeconds   int	`json:"defaultRunTimeoutSeconds"`
+
+	// Not applied when running against an external RPC endpoint, since no network gets set up
+	SetupTimeoutPerAdditionalNodeSeconds int	`json:"setupTimeoutPerAdditionalNodeSeconds"`
+
+	// Overrides by test name
+	PerTest map[string]TestTimeoutOverride	`json:"perTest"`
+}
+
+// Fields left at zero don't override the test's timeout
+type TestTimeoutOverride struct {
+	SetupTimeoutSeconds int	`json:"setupTimeoutSeconds"`
+	RunTimeoutSeconds   int	`json:"runTimeoutSeconds"`
+}
+
+func NewDefaultTimeoutConfig() TimeoutConfig {
+	return TimeoutConfig{
+		DefaultSetupTimeoutSeconds:           int(defaultSetupTimeout / time.Second),
+		DefaultRunTimeoutSeconds:             int(defaultRunTimeout / time.Second),
+		SetupTimeoutPerAdditionalNodeSeconds: defaultSetupTimeoutPerAdditionalNodeSeconds,
+		PerTest:                              map[string]TestTimeoutOverride{},
+	}
+}
+
+// Timeouts left at zero are unset; set ones must be longer than TimeoutBuffer, or tests would have no time left to run in
+func (config TimeoutConfig) Validate() error {
+	if err := validateTimeoutSeconds(config.DefaultSetupTimeoutSeconds); err != nil {
+		return stacktrace.Propagate(err, "Invalid default setup timeout")
+	}
+	if err := validateTimeoutSeconds(config.DefaultRunTimeoutSeconds); err != nil {
+		return stacktrace.Propagate(err, "Invalid default run timeout")
+	}
+	if config.SetupTimeoutPerAdditionalNodeSeconds < 0 {
+		return stacktrace.NewError("Setup timeout per additional node must be >= 0 seconds, but was %v", config.SetupTimeoutPerAdditionalNodeSeconds)
+	}
+	for testName, override := range config.PerTest {
+		if err := validateTimeoutSeconds(override.SetupTimeoutSeconds); err != nil {
+			return stacktrace.Propagate(err, "Invalid setup timeout override for test '%v'", testName)
+		}
+		if err := validateTimeoutSeconds(override.RunTimeoutSeconds); err != nil {
+			return stacktrace.Propagate(err, "Invalid run timeout override for test '%v'", testName)
+		}
+	}
+	return nil
+}
+
+// Checks that every test with an override is registered, so that a misspelled test name doesn't silently leave a test
+//  with its default timeouts; must be called after every test has been registered
+func (config TimeoutConfig) ValidateTestNames() error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	for testName := range config.PerTest {
+		if _, found := registeredTests[testName]; !found {
+			return stacktrace.NewError("Timeouts were overridden for test '%v', but no test with that name is registered", testName)
+		}
+	}
+	return nil
+}
+
+// Returns the setup and run timeouts for the test, given the dependencies it'll be built with
+func (config TimeoutConfig) getTimeouts(test RegisteredTest, dependencies TestDependencies) (time.Duration, time.Duration) {
+	override := config.PerTest[test.Name]
+	setupTimeout := getBaseTimeout(override.SetupTimeoutSeconds, test.Metadata.SetupTimeout, config.DefaultSetupTimeoutSeconds, defaultSetupTimeout)
+	runTimeout := getBaseTimeout(override.RunTimeoutSeconds, test.Metadata.RunTimeout, config.DefaultRunTimeoutSeconds, defaultRunTimeout)
+
+	if !dependencies.NetworkConfig.ExternalRpc.IsEnabled() {
+		numAdditionalNodes := dependencies.NetworkConfig.NumAdditionalNodes
+		setupTimeout += time.Duration(numAdditionalNodes * config.SetupTimeoutPerAdditionalNodeSeconds) * time.Second
+	}
+	return setupTimeout, runTimeout
+}
+
+// Returns the first timeout that's set, from most to least specific
+func getBaseTimeout(overrideSeconds int, metadataTimeout time.Duration, configDefaultSeconds int, builtInDefault time.Duration) time.Duration {
+	if overrideSeconds > 0 {
+		return time.Duration(overrideSeconds) * time.Second
+	}
+	if metadataTimeout > 0 {
+		return metadataTimeout
+	}
+	if configDefaultSeconds > 0 {
+		return time.Duration(configDefaultSeconds) * time.Second
+	}
+	return builtInDefault
+}
+
+func validateTimeoutSeconds(timeoutSeconds int) error {
+	if timeoutSeconds == 0 {
+		return nil
+	}
+	return validateTimeout(time.Duration(timeoutSeconds) * time.Second)
+}
+
+// Timeouts are cut short by TimeoutBuffer to get the tests' own deadlines, which must be left positive
+func validateTimeout(timeout time.Duration) error {
+	if timeout <= TimeoutBuffer {
+		return stacktrace.NewError("Timeouts must be longer than the %v buffer that tests leave before them, but was %v", TimeoutBuffer, timeout)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package test_catalog
+
+import (
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"testing"
+	"time"
+)
+
+const (
+	timeoutTestName = "timedTest"
+)
+
+// A timeout is taken from, in order: the test's override, its metadata, the config's default, then the built-in default
+func TestGetTimeoutsPrecedence(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		override       TestTimeoutOverride
+		metadata       TestMetadata
+		defaultSeconds int
+
+		expectedSetupTimeout time.Duration
+		expectedRunTimeout   time.Duration
+	}{
+		{
+			name:                 "Built-in defaults",
+			expectedSetupTimeout: defaultSetupTimeout,
+			expectedRunTimeout:   defaultRunTimeout,
+		},
+		{
+			name:                 "Config defaults beat built-in defaults",
+			defaultSeconds:       40,
+			expectedSetupTimeout: 40 * time.Second,
+			expectedRunTimeout:   40 * time.Second,
+		},
+		{
+			name:                 "Metadata beats config defaults",
+			metadata:             TestMetadata{SetupTimeout: 50 * time.Second, RunTimeout: 60 * time.Second},
+			defaultSeconds:       40,
+			expectedSetupTimeout: 50 * time.Second,
+			expectedRunTimeout:   60 * time.Second,
+		},
+		{
+			name:                 "Override beats metadata",
+			override:             TestTimeoutOverride{SetupTimeoutSeconds: 70, RunTimeoutSeconds: 80},
+			metadata:             TestMetadata{SetupTimeout: 50 * time.Second, RunTimeout: 60 * time.Second},
+			defaultSeconds:       40,
+			expectedSetupTimeout: 70 * time.Second,
+			expectedRunTimeout:   80 * time.Second,
+		},
+		{
+			name:                 "Each timeout falls back on its own",
+			override:             TestTimeoutOverride{SetupTimeoutSeconds: 0, RunTimeoutSeconds: 80},
+			metadata:             TestMetadata{SetupTimeout: 50 * time.Second, RunTimeout: 0},
+			expectedSetupTimeout: 50 * time.Second,
+			expectedRunTimeout:   80 * time.Second,
+		},
+	}
+
+	for _, testCase := range testCases {
+		config := TimeoutConfig{
+			DefaultSetupTimeoutSeconds:           testCase.defaultSeconds,
+			DefaultRunTimeoutSeconds:             testCase.defaultSeconds,
+			SetupTimeoutPerAdditionalNodeSeconds: defaultSetupTimeoutPerAdditionalNodeSeconds,
+			PerTest: map[string]TestTimeoutOverride{
+				timeoutTestName: testCase.override,
+				"otherTest":     {SetupTimeoutSeconds: 999, RunTimeoutSeconds: 999},
+			},
+		}
+		test := RegisteredTest{Name: timeoutTestName, Metadata: testCase.metadata}
+		setupTimeout, runTimeout := config.getTimeouts(test, TestDependencies{})
+		if setupTimeout != testCase.expectedSetupTimeout {
+			t.Errorf("%v: expected setup timeout %v, but was %v", testCase.name, testCase.expectedSetupTimeout, setupTimeout)
+		}
+		if runTimeout != testCase.expectedRunTimeout {
+			t.Errorf("%v: expected run timeout %v, but was %v", testCase.name, testCase.expectedRunTimeout, runTimeout)
+		}
+	}
+}
+
+// Only the setup timeout scales with the number of nodes past the bootstrap stakers, and only when a network gets set up
+func TestGetTimeoutsScalesSetupTimeoutWithNodeCount(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		numAdditionalNodes int
+		externalRpcUrl     string
+
+		expectedSetupTimeout time.Duration
+	}{
+		{
+			name:                 "Bootstrap stakers only",
+			expectedSetupTimeout: 100 * time.Second,
+		},
+		{
+			name:                 "Additional nodes",
+			numAdditionalNodes:   5,
+			expectedSetupTimeout: 175 * time.Second,
+		},
+		{
+			name:                 "External RPC endpoint",
+			numAdditionalNodes:   5,
+			externalRpcUrl:       "ws://host.docker.internal:9650/ext/bc/C/ws",
+			expectedSetupTimeout: 100 * time.Second,
+		},
+	}
+
+	config := TimeoutConfig{
+		DefaultSetupTimeoutSeconds:           100,
+		DefaultRunTimeoutSeconds:             60,
+		SetupTimeoutPerAdditionalNodeSeconds: 15,
+		PerTest:                              map[string]TestTimeoutOverride{},
+	}
+	test := RegisteredTest{Name: timeoutTestName}
+	for _, testCase := range testCases {
+		dependencies := TestDependencies{
+			NetworkConfig: networks_impl.SmartContractAvalancheNetworkConfig{
+				NumAdditionalNodes: testCase.numAdditionalNodes,
+				ExternalRpc:        networks_impl.ExternalRpcConfig{RpcUrl: testCase.externalRpcUrl},
+			},
+		}
+		setupTimeout, runTimeout := config.getTimeouts(test, dependencies)
+		if setupTimeout != testCase.expectedSetupTimeout {
+			t.Errorf("%v: expected setup timeout %v, but was %v", testCase.name, testCase.expectedSetupTimeout, setupTimeout)
+		}
+		if runTimeout != 60 * time.Second {
+			t.Errorf("%v: expected the run timeout not to scale, but it was %v", testCase.name, runTimeout)
+		}
+	}
+}
+
+// Registering a test builds it with the scaled timeouts, including nodes added because of the test's metadata
+func TestBuildScalesSetupTimeoutWithMetadataMinNodes(t *testing.T) {
+	var builtDependencies TestDependencies
+	test := RegisteredTest{
+		Name: timeoutTestName,
+		Metadata: TestMetadata{
+			MinNumAdditionalNodes: 2,
+		},
+		constructor: func(dependencies TestDependencies) testsuite.Test {
+			builtDependencies = dependencies
+			return nil
+		},
+	}
+	config := TimeoutConfig{
+		DefaultSetupTimeoutSeconds:           100,
+		DefaultRunTimeoutSeconds:             60,
+		SetupTimeoutPerAdditionalNodeSeconds: 15,
+		PerTest:                              map[string]TestTimeoutOverride{},
+	}
+	test.Build(TestDependencies{}, config)
+	if builtDependencies.SetupTimeout != 130 * time.Second {
+		t.Fatalf("Expected the setup timeout to scale with the metadata's minimum node count to 130s, but was %v", builtDependencies.SetupTimeout)
+	}
+}
+
+func TestTimeoutConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		config TimeoutConfig
+
+		isErrExpected bool
+	}{
+		{
+			name:   "Defaults",
+			config: NewDefaultTimeoutConfig(),
+		},
+		{
+			name:   "Everything unset",
+			config: TimeoutConfig{},
+		},
+		{
+			name: "Just over the buffer",
+			config: TimeoutConfig{
+				DefaultSetupTimeoutSeconds: 11,
+				DefaultRunTimeoutSeconds:   11,
+				PerTest:                    map[string]TestTimeoutOverride{timeoutTestName: {SetupTimeoutSeconds: 11, RunTimeoutSeconds: 11}},
+			},
+		},
+		{
+			name:          "Negative default setup timeout",
+			config:        TimeoutConfig{DefaultSetupTimeoutSeconds: -1},
+			isErrExpected: true,
+		},
+		{
+			name:          "Default setup timeout equal to the buffer",
+			config:        TimeoutConfig{DefaultSetupTimeoutSeconds: 10},
+			isErrExpected: true,
+		},
+		{
+			name:          "Default run timeout below the buffer",
+			config:        TimeoutConfig{DefaultRunTimeoutSeconds: 5},
+			isErrExpected: true,
+		},
+		{
+			name:          "Negative setup timeout per additional node",
+			config:        TimeoutConfig{SetupTimeoutPerAdditionalNodeSeconds: -1},
+			isErrExpected: true,
+		},
+		{
+			name: "Setup timeout override equal to the buffer",
+			config: TimeoutConfig{
+				PerTest: map[string]TestTimeoutOverride{timeoutTestName: {SetupTimeoutSeconds: 10, RunTimeoutSeconds: 0}},
+			},
+			isErrExpected: true,
+		},
+		{
+			name: "Negative run timeout override",
+			config: TimeoutConfig{
+				PerTest: map[string]TestTimeoutOverride{timeoutTestName: {SetupTimeoutSeconds: 0, RunTimeoutSeconds: -1}},
+			},
+			isErrExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.isErrExpected && err == nil {
+			t.Errorf("%v: expected the timeout config to be invalid, but it was valid", testCase.name)
+		}
+		if !testCase.isErrExpected && err != nil {
+			t.Errorf("%v: expected the timeout config to be valid, but got an error: %v", testCase.name, err)
+		}
+	}
+}
+
+func TestValidateRegistrationRejectsTimeoutsWithinBuffer(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		metadata TestMetadata
+
+		isErrExpected bool
+	}{
+		{name: "Unset", metadata: TestMetadata{}},
+		{name: "Over the buffer", metadata: TestMetadata{SetupTimeout: 11 * time.Second, RunTimeout: 11 * time.Second}},
+		{name: "Setup timeout equal to the buffer", metadata: TestMetadata{SetupTimeout: TimeoutBuffer}, isErrExpected: true},
+		{name: "Run timeout below the buffer", metadata: TestMetadata{RunTimeout: time.Second}, isErrExpected: true},
+		{name: "Negative run timeout", metadata: TestMetadata{RunTimeout: -time.Second}, isErrExpected: true},
+	}
+	constructor := func(dependencies TestDependencies) testsuite.Test {
+		return nil
+	}
+	for _, testCase := range testCases {
+		err := validateRegistration(timeoutTestName, testCase.metadata, constructor)
+		if testCase.isErrExpected && err == nil {
+			t.Errorf("%v: expected the registration to be invalid, but it was valid", testCase.name)
+		}
+		if !testCase.isErrExpected && err != nil {
+			t.Errorf("%v: expected the registration to be valid, but got an error: %v", testCase.name, err)
+		}
+	}
+}
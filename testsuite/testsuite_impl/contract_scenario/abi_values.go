@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/palantir/stacktrace"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	hexPrefix = "0x"
+
+	decimalBase = 10
+	hexBase     = 16
+)
+
+var (
+	bigIntType = reflect.TypeOf(&big.Int{})
+)
+
+// Looks up the address of an account or contract by the name a scenario gives it
+type addressResolver func(name string) (common.Address, bool)
+
+// Converts a value from a scenario file (as decoded from JSON, with numbers as json.Number) to the Go value that the abi
+//  package expects for the given type, e.g. *big.Int for uint256 or [32]byte for bytes32
+// Integers can be given as JSON numbers or as decimal or 0x-prefixed hex strings, since JSON numbers can't hold
+//  256-bit values precisely; addresses can be given as hex or as the name of an account or contract in the scenario;
+//  bytes are given as hex
+func convertToAbiValue(abiType abi.Type, value interface{}, resolveAddress addressResolver) (interface{}, error) {
+	switch abiType.T {
+	case abi.IntTy, abi.UintTy:
+		integer, err := parseBigInt(value)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Expected an integer for type '%v'", abiType)
+		}
+		if err := checkIntegerFits(abiType, integer); err != nil {
+			return nil, err
+		}
+		goType := abiType.GetType()
+		if goType == bigIntType {
+			return integer, nil
+		}
+		result := reflect.New(goType).Elem()
+		if abiType.T == abi.UintTy {
+			result.SetUint(integer.Uint64())
+		} else {
+			result.SetInt(integer.Int64())
+		}
+		return result.Interface(), nil
+	case abi.BoolTy:
+		result, ok := value.(bool)
+		if !ok {
+			return nil, stacktrace.NewError("Expected a boolean for type 'bool', but got '%v'", value)
+		}
+		return result, nil
+	case abi.StringTy:
+		result, ok := value.(string)
+		if !ok {
+			return nil, stacktrace.NewError("Expected a string for type 'string', but got '%v'", value)
+		}
+		return result, nil
+	case abi.AddressTy:
+		str, ok := value.(string)
+		if !ok {
+			return nil, stacktrace.NewError("Expected a hex address or account/contract name for type 'address', but got '%v'", value)
+		}
+		if strings.HasPrefix(str, hexPrefix) {
+			if !common.IsHexAddress(str) {
+				return nil, stacktrace.NewError("'%v' isn't a valid hex address", str)
+			}
+			return common.HexToAddress(str), nil
+		}
+		address, found := resolveAddress(str)
+		if !found {
+			return nil, stacktrace.NewError("'%v' is neither a hex address nor the name of an account or contract in the scenario", str)
+		}
+		return address, nil
+	case abi.BytesTy:
+		return parseHexBytes(value)
+	case abi.FixedBytesTy:
+		bytes, err := parseHexBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes) != abiType.Size {
+			return nil, stacktrace.NewError("Expected %v bytes for type '%v', but got %v", abiType.Size, abiType, len(bytes))
+		}
+		result := reflect.New(abiType.GetType()).Elem()
+		reflect.Copy(result, reflect.ValueOf(bytes))
+		return result.Interface(), nil
+	case abi.SliceTy, abi.ArrayTy:
+		elems, ok := value.([]interface{})
+		if !ok {
+			return nil, stacktrace.NewError("Expected a list for type '%v', but got '%v'", abiType, value)
+		}
+		var result reflect.Value
+		if abiType.T == abi.SliceTy {
+			result = reflect.MakeSlice(abiType.GetType(), len(elems), len(elems))
+		} else {
+			if len(elems) != abiType.Size {
+				return nil, stacktrace.NewError("Expected %v elements for type '%v', but got %v", abiType.Size, abiType, len(elems))
+			}
+			result = reflect.New(abiType.GetType()).Elem()
+		}
+		for i, elem := range elems {
+			convertedElem, err := convertToAbiValue(*abiType.Elem, elem, resolveAddress)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "An error occurred converting element %v", i)
+			}
+			result.Index(i).Set(reflect.ValueOf(convertedElem))
+		}
+		return result.Interface(), nil
+	default:
+		return nil, stacktrace.NewError("Type '%v' isn't supported in scenario files", abiType)
+	}
+}
+
+// Converts the values for each of the arguments in order
+func convertToAbiValues(arguments abi.Arguments, values []interface{}, resolveAddress addressResolver) ([]interface{}, error) {
+	if len(values) != len(arguments) {
+		return nil, stacktrace.NewError("Expected %v values, but got %v", len(arguments), len(values))
+	}
+	result := []interface{}{}
+	for i, argument := range arguments {
+		converted, err := convertToAbiValue(argument.Type, values[i], resolveAddress)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred converting the value for argument %v ('%v')", i, argument.Name)
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+// Indexed event arguments of dynamic types are stored as the keccak256 hash of their value, so expected values for them
+//  must be hashed the same way before they can be compared
+func convertToIndexedTopicValue(abiType abi.Type, value interface{}, resolveAddress addressResolver) (interface{}, error) {
+	switch abiType.T {
+	case abi.StringTy:
+		str, ok := value.(string)
+		if !ok {
+			return nil, stacktrace.NewError("Expected a string for type 'string', but got '%v'", value)
+		}
+		return crypto.Keccak256Hash([]byte(str)), nil
+	case abi.BytesTy:
+		bytes, err := parseHexBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256Hash(bytes), nil
+	case abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return nil, stacktrace.NewError("Indexed event arguments of type '%v' can't be asserted on", abiType)
+	default:
+		return convertToAbiValue(abiType, value, resolveAddress)
+	}
+}
+
+// Formats a value unpacked by the abi package (or converted by convertToAbiValue) so that expected and actual values
+//  can be compared, and shown in errors, regardless of their Go types
+func formatAbiValue(value interface{}) string {
+	if integer, ok := value.(*big.Int); ok {
+		return integer.String()
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		// Byte arrays and slices cover addresses, hashes, bytes, and bytesN
+		if reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+			bytes := make([]byte, reflectValue.Len())
+			reflect.Copy(reflect.ValueOf(bytes), reflectValue)
+			return hexutil.Encode(bytes)
+		}
+		elems := []string{}
+		for i := 0; i < reflectValue.Len(); i++ {
+			elems = append(elems, formatAbiValue(reflectValue.Index(i).Interface()))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case reflect.String:
+		return strconv.Quote(reflectValue.String())
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func parseBigInt(value interface{}) (*big.Int, error) {
+	var str string
+	switch typedValue := value.(type) {
+	case json.Number:
+		str = typedValue.String()
+	case string:
+		str = strings.TrimSpace(typedValue)
+	default:
+		return nil, stacktrace.NewError("Expected a number or a numeric string, but got '%v'", value)
+	}
+
+	result := new(big.Int)
+	var ok bool
+	if strings.HasPrefix(str, hexPrefix) {
+		_, ok = result.SetString(strings.TrimPrefix(str, hexPrefix), hexBase)
+	} else {
+		_, ok = result.SetString(str, decimalBase)
+	}
+	if !ok {
+		return nil, stacktrace.NewError("'%v' isn't a valid integer", str)
+	}
+	return result, nil
+}
+
+func checkIntegerFits(abiType abi.Type, integer *big.Int) error {
+	if abiType.T == abi.UintTy {
+		if integer.Sign() < 0 || integer.BitLen() > abiType.Size {
+			return stacktrace.NewError("%v doesn't fit in type '%v'", integer, abiType)
+		}
+		return nil
+	}
+	// Signed integers range from -2^(size-1) to 2^(size-1)-1
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(abiType.Size - 1))
+	if integer.Cmp(limit) >= 0 || integer.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return stacktrace.NewError("%v doesn't fit in type '%v'", integer, abiType)
+	}
+	return nil
+}
+
+func parseHexBytes(value interface{}) ([]byte, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, stacktrace.NewError("Expected a 0x-prefixed hex string for a bytes type, but got '%v'", value)
+	}
+	result, err := hexutil.Decode(str)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "'%v' isn't a valid 0x-prefixed hex string", str)
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"testing"
+)
+
+var (
+	aliceAddress = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bobAddress   = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+)
+
+func TestConvertToAbiValue(t *testing.T) {
+	testCases := []struct {
+		abiType string
+		value   interface{}
+
+		// Ignored if an error is expected
+		expectedFormattedValue string
+		isErrExpected          bool
+	}{
+		{abiType: "uint8", value: json.Number("255"), expectedFormattedValue: "255"},
+		{abiType: "uint8", value: json.Number("256"), isErrExpected: true},
+		{abiType: "uint8", value: json.Number("-1"), isErrExpected: true},
+		{abiType: "int8", value: "-128", expectedFormattedValue: "-128"},
+		{abiType: "int8", value: "128", isErrExpected: true},
+		{abiType: "int64", value: json.Number("-42"), expectedFormattedValue: "-42"},
+		{abiType: "uint256", value: "0xff", expectedFormattedValue: "255"},
+		{abiType: "uint256", value: " 115792089237316195423570985008687907853269984665640564039457584007913129639935 ", expectedFormattedValue: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{abiType: "uint256", value: "1.5", isErrExpected: true},
+		{abiType: "uint256", value: true, isErrExpected: true},
+		{abiType: "bool", value: true, expectedFormattedValue: "true"},
+		{abiType: "bool", value: "true", isErrExpected: true},
+		{abiType: "string", value: "Hello World!", expectedFormattedValue: `"Hello World!"`},
+		{abiType: "string", value: json.Number("1"), isErrExpected: true},
+		{abiType: "address", value: "0x0000000000000000000000000000000000000001", expectedFormattedValue: "0x0000000000000000000000000000000000000001"},
+		{abiType: "address", value: "alice", expectedFormattedValue: "0x00000000000000000000000000000000000a11ce"},
+		{abiType: "address", value: "mallory", isErrExpected: true},
+		{abiType: "address", value: "0x1234", isErrExpected: true},
+		{abiType: "bytes", value: "0x0102", expectedFormattedValue: "0x0102"},
+		{abiType: "bytes", value: "0102", isErrExpected: true},
+		{abiType: "bytes2", value: "0x0102", expectedFormattedValue: "0x0102"},
+		{abiType: "bytes2", value: "0x01", isErrExpected: true},
+		{abiType: "uint256[]", value: []interface{}{json.Number("1"), "0x2"}, expectedFormattedValue: "[1, 2]"},
+		{abiType: "uint8[]", value: json.Number("1"), isErrExpected: true},
+		{abiType: "uint16[2]", value: []interface{}{json.Number("1"), json.Number("2")}, expectedFormattedValue: "[1, 2]"},
+		{abiType: "uint16[2]", value: []interface{}{json.Number("1")}, isErrExpected: true},
+		{abiType: "uint16[2]", value: []interface{}{json.Number("1"), json.Number("70000")}, isErrExpected: true},
+		{abiType: "address[]", value: []interface{}{"alice", "bob"}, expectedFormattedValue: "[0x00000000000000000000000000000000000a11ce, 0x0000000000000000000000000000000000000b0b]"},
+		{abiType: "function", value: "0x00", isErrExpected: true},
+	}
+	for _, testCase := range testCases {
+		abiType := newAbiType(t, testCase.abiType)
+		converted, err := convertToAbiValue(abiType, testCase.value, resolveTestAddress)
+		if testCase.isErrExpected {
+			if err == nil {
+				t.Errorf("Expected an error converting '%v' to type '%v', but got %v", testCase.value, testCase.abiType, formatAbiValue(converted))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("An error occurred converting '%v' to type '%v': %v", testCase.value, testCase.abiType, err)
+			continue
+		}
+		if formatted := formatAbiValue(converted); formatted != testCase.expectedFormattedValue {
+			t.Errorf("Expected '%v' converted to type '%v' to be %v, but was %v", testCase.value, testCase.abiType, testCase.expectedFormattedValue, formatted)
+		}
+	}
+}
+
+func TestConvertToAbiValuesChecksNumValues(t *testing.T) {
+	arguments := abi.Arguments{
+		{Name: "to", Type: newAbiType(t, "address"), Indexed: false},
+		{Name: "value", Type: newAbiType(t, "uint256"), Indexed: false},
+	}
+	if _, err := convertToAbiValues(arguments, []interface{}{"alice"}, resolveTestAddress); err == nil {
+		t.Fatalf("Expected an error converting too few values, but got none")
+	}
+	converted, err := convertToAbiValues(arguments, []interface{}{"alice", json.Number("5")}, resolveTestAddress)
+	if err != nil {
+		t.Fatalf("An error occurred converting the values: %v", err)
+	}
+	if formatted := formatAbiValue(converted); formatted != "[0x00000000000000000000000000000000000a11ce, 5]" {
+		t.Fatalf("Expected the values to convert in argument order, but got %v", formatted)
+	}
+}
+
+// Indexed arguments of dynamic types are matched by the hash of their value, since that's all that's in the topic
+func TestConvertExpectedEventArg(t *testing.T) {
+	testCases := []struct {
+		abiType string
+		indexed bool
+		value   interface{}
+
+		// Ignored if an error is expected
+		expectedFormattedValue string
+		isErrExpected          bool
+	}{
+		{abiType: "string", indexed: true, value: "tag", expectedFormattedValue: crypto.Keccak256Hash([]byte("tag")).Hex()},
+		{abiType: "string", indexed: false, value: "tag", expectedFormattedValue: `"tag"`},
+		{abiType: "string", indexed: true, value: json.Number("1"), isErrExpected: true},
+		{abiType: "bytes", indexed: true, value: "0x0102", expectedFormattedValue: crypto.Keccak256Hash([]byte{1, 2}).Hex()},
+		{abiType: "bytes", indexed: false, value: "0x0102", expectedFormattedValue: "0x0102"},
+		{abiType: "address", indexed: true, value: "bob", expectedFormattedValue: "0x0000000000000000000000000000000000000b0b"},
+		{abiType: "uint256", indexed: true, value: "5", expectedFormattedValue: "5"},
+		{abiType: "uint256[]", indexed: true, value: []interface{}{"1"}, isErrExpected: true},
+		{abiType: "uint256[]", indexed: false, value: []interface{}{"1"}, expectedFormattedValue: "[1]"},
+	}
+	for _, testCase := range testCases {
+		argument := abi.Argument{Name: "arg", Type: newAbiType(t, testCase.abiType), Indexed: testCase.indexed}
+		converted, err := convertExpectedEventArg(argument, testCase.value, resolveTestAddress)
+		if testCase.isErrExpected {
+			if err == nil {
+				t.Errorf(
+					"Expected an error converting '%v' for a %v argument (indexed: %v), but got %v",
+					testCase.value,
+					testCase.abiType,
+					testCase.indexed,
+					formatAbiValue(converted))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("An error occurred converting '%v' for a %v argument (indexed: %v): %v", testCase.value, testCase.abiType, testCase.indexed, err)
+			continue
+		}
+		if formatted := formatAbiValue(converted); formatted != testCase.expectedFormattedValue {
+			t.Errorf(
+				"Expected '%v' for a %v argument (indexed: %v) to convert to %v, but was %v",
+				testCase.value,
+				testCase.abiType,
+				testCase.indexed,
+				testCase.expectedFormattedValue,
+				formatted)
+		}
+	}
+}
+
+func newAbiType(t *testing.T, typeStr string) abi.Type {
+	result, err := abi.NewType(typeStr, "", nil)
+	if err != nil {
+		t.Fatalf("An error occurred creating ABI type '%v': %v", typeStr, err)
+	}
+	return result
+}
+
+func resolveTestAddress(name string) (common.Address, bool) {
+	address, found := map[string]common.Address{
+		"alice": aliceAddress,
+		"bob":   bobAddress,
+	}[name]
+	return address, found
+}
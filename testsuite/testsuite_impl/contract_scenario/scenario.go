@@ -0,0 +1,452 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ghodss/yaml"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/palantir/stacktrace"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	jsonFileExt = ".json"
+	yamlFileExt = ".yaml"
+	ymlFileExt  = ".yml"
+
+	// The account that steps without a "from" send from, which the backend funds during setup
+	defaultAccountName = "default"
+)
+
+// A contract test written as data rather than Go, for people who write Solidity but not Go; steps run in order, and
+//  the scenario fails at the first step whose expectations aren't met
+// See smart_contracts/scenarios for examples
+type Scenario struct {
+	// Becomes the name of the test, so must be unique across the suite
+	Name string `json:"name"`
+
+	Description string `json:"description"`
+
+	Tags []string `json:"tags"`
+
+	// Optional; left at zero, the test catalog's default is used
+	RunTimeoutSeconds int `json:"runTimeoutSeconds"`
+
+	// Names of the funded accounts to create, which steps can send from and pass as address args
+	Accounts []string `json:"accounts"`
+
+	Steps []ScenarioStep `json:"steps"`
+
+	// The file the scenario was loaded from, for errors
+	sourceFilepath string
+}
+
+// Exactly one action must be set
+type ScenarioStep struct {
+	// Optional; shown in the log and in errors
+	Description string `json:"description"`
+
+	Deploy        *DeployStep        `json:"deploy"`
+	Transact      *TransactStep      `json:"transact"`
+	Call          *CallStep          `json:"call"`
+	Wait          *WaitStep          `json:"wait"`
+	AssertBalance *AssertBalanceStep `json:"assertBalance"`
+}
+
+// Expectations and options shared by the steps that send transactions
+type TransactionOptions struct {
+	// Account to send from; defaults to the default account
+	From string `json:"from"`
+
+	// Amount of wei to send along, for payable functions and constructors
+	Value string `json:"value"`
+
+	// If set, the transaction is sent with this gas limit rather than the one chosen by the gas strategy
+	GasLimit uint64 `json:"gasLimit"`
+
+	// If true, the step doesn't wait for the transaction to be accepted, and its expectations are checked by the next
+	//  wait step instead; lets several transactions land in the same block
+	NoWait bool `json:"noWait"`
+
+	// If set, the transaction must revert with a reason containing this string (an empty string matches any reason)
+	// Reverting transactions fail gas estimation, so unless a gas limit is given, they get sent with a fixed one
+	ExpectRevert *string `json:"expectRevert"`
+
+	// Events that the transaction must emit, in this order, though other events may come between them
+	ExpectEvents []ExpectedEvent `json:"expectEvents"`
+}
+
+type ExpectedEvent struct {
+	// Name of the event in the contract's ABI
+	Event string `json:"event"`
+
+	// Contract name (as given by "as") that must emit the event; defaults to the contract the transaction was sent to
+	Contract string `json:"contract"`
+
+	// Values of the event's arguments by name; arguments that aren't listed can have any value
+	Args map[string]interface{} `json:"args"`
+}
+
+// Deploys a contract from the contract registry
+type DeployStep struct {
+	// Name of the contract in the contract registry
+	Contract string `json:"contract"`
+
+	// Name that later steps use for the deployed contract; defaults to the contract's name
+	As string `json:"as"`
+
+	// Constructor arguments
+	Args []interface{} `json:"args"`
+
+	TransactionOptions
+}
+
+// Sends a transaction calling a contract method
+type TransactStep struct {
+	// Name of a deployed contract, as given by "as"
+	Contract string `json:"contract"`
+
+	Method string `json:"method"`
+
+	Args []interface{} `json:"args"`
+
+	TransactionOptions
+}
+
+// Calls a contract method without sending a transaction
+type CallStep struct {
+	// Name of a deployed contract, as given by "as"
+	Contract string `json:"contract"`
+
+	Method string `json:"method"`
+
+	Args []interface{} `json:"args"`
+
+	// Account to call from; defaults to the default account
+	From string `json:"from"`
+
+	// If set, the method's return values must be these, in order
+	Expect []interface{} `json:"expect"`
+
+	// If set, the call must revert with a reason containing this string (an empty string matches any reason)
+	ExpectRevert *string `json:"expectRevert"`
+}
+
+// Waits for every transaction sent with "noWait" to be accepted, and checks their expectations
+type WaitStep struct {}
+
+// Checks the wei balance of an account or contract; at least one bound must be given
+type AssertBalanceStep struct {
+	// Name of an account or contract in the scenario, or a hex address
+	Of string `json:"of"`
+
+	Equals  string `json:"equals"`
+	AtLeast string `json:"atLeast"`
+	AtMost  string `json:"atMost"`
+}
+
+// Loads every JSON and YAML scenario file in the directory and its subdirectories, checking each one against the
+//  contract registry so that mistakes surface when the suite starts rather than partway through a test
+func LoadScenarios(dirpath string, contractRegistry *contract_registry.ContractRegistry) ([]*Scenario, error) {
+	scenarioFilepaths := []string{}
+	walkFunc := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred walking '%v'", path)
+		}
+		if info.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case jsonFileExt, yamlFileExt, ymlFileExt:
+			scenarioFilepaths = append(scenarioFilepaths, path)
+		}
+		return nil
+	}
+	if err := filepath.Walk(dirpath, walkFunc); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred finding the scenario files in '%v'", dirpath)
+	}
+	sort.Strings(scenarioFilepaths)
+
+	result := []*Scenario{}
+	scenarioFilepathsByName := map[string]string{}
+	for _, scenarioFilepath := range scenarioFilepaths {
+		scenario, err := parseScenarioFile(scenarioFilepath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred parsing scenario file '%v'", scenarioFilepath)
+		}
+		if otherFilepath, found := scenarioFilepathsByName[scenario.Name]; found {
+			return nil, stacktrace.NewError(
+				"Scenario '%v' is defined in both '%v' and '%v'",
+				scenario.Name,
+				otherFilepath,
+				scenarioFilepath)
+		}
+		scenarioFilepathsByName[scenario.Name] = scenarioFilepath
+		if err := scenario.validate(contractRegistry); err != nil {
+			return nil, stacktrace.Propagate(err, "Scenario '%v' in '%v' is invalid", scenario.Name, scenarioFilepath)
+		}
+		result = append(result, scenario)
+	}
+	return result, nil
+}
+
+func parseScenarioFile(scenarioFilepath string) (*Scenario, error) {
+	contents, err := ioutil.ReadFile(scenarioFilepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred reading the file")
+	}
+	// YAML gets converted to JSON, so that both formats go through the same decoding
+	jsonContents := contents
+	if ext := strings.ToLower(filepath.Ext(scenarioFilepath)); ext == yamlFileExt || ext == ymlFileExt {
+		jsonContents, err = yaml.YAMLToJSON(contents)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred converting the YAML to JSON")
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonContents))
+	// Large integers must keep their precision
+	decoder.UseNumber()
+	// Misspelled fields would otherwise silently turn into missing expectations
+	decoder.DisallowUnknownFields()
+	scenario := &Scenario{}
+	if err := decoder.Decode(scenario); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred decoding the scenario")
+	}
+	scenario.sourceFilepath = scenarioFilepath
+	return scenario, nil
+}
+
+// Checks everything that can be checked without a chain: that each step has one action, that the contracts, methods,
+//  events, accounts, and names it refers to exist, and that its values convert to their ABI types
+func (scenario Scenario) validate(contractRegistry *contract_registry.ContractRegistry) error {
+	if strings.TrimSpace(scenario.Name) == "" {
+		return stacktrace.NewError("Scenario name is empty")
+	}
+	if scenario.RunTimeoutSeconds < 0 {
+		return stacktrace.NewError("Run timeout must be >= 0 seconds")
+	}
+	if len(scenario.Steps) == 0 {
+		return stacktrace.NewError("Scenario has no steps")
+	}
+
+	// Addresses don't exist until the scenario runs, so names just need to be known
+	knownNames := map[string]bool{defaultAccountName: true}
+	accountNames := map[string]bool{defaultAccountName: true}
+	for _, accountName := range scenario.Accounts {
+		if strings.TrimSpace(accountName) == "" {
+			return stacktrace.NewError("Account names may not be empty")
+		}
+		if knownNames[accountName] {
+			return stacktrace.NewError("Account '%v' is declared more than once, or uses the reserved name '%v'", accountName, defaultAccountName)
+		}
+		knownNames[accountName] = true
+		accountNames[accountName] = true
+	}
+	resolveAddress := func(name string) (common.Address, bool) {
+		return common.Address{}, knownNames[name]
+	}
+	// Deployed contracts' artifacts, by name
+	deployedArtifacts := map[string]*contract_registry.ContractArtifact{}
+
+	for i, step := range scenario.Steps {
+		if err := validateStep(step, contractRegistry, accountNames, knownNames, deployedArtifacts, resolveAddress); err != nil {
+			return stacktrace.Propagate(err, "Step %v (%v) is invalid", i + 1, step.describe())
+		}
+	}
+	return nil
+}
+
+func validateStep(
+		step ScenarioStep,
+		contractRegistry *contract_registry.ContractRegistry,
+		accountNames map[string]bool,
+		knownNames map[string]bool,
+		deployedArtifacts map[string]*contract_registry.ContractArtifact,
+		resolveAddress addressResolver) error {
+	numActions := 0
+	for _, isSet := range []bool{step.Deploy != nil, step.Transact != nil, step.Call != nil, step.Wait != nil, step.AssertBalance != nil} {
+		if isSet {
+			numActions++
+		}
+	}
+	if numActions != 1 {
+		return stacktrace.NewError("Each step must have exactly one of deploy, transact, call, wait, or assertBalance, but this one has %v", numActions)
+	}
+
+	switch {
+	case step.Deploy != nil:
+		artifact, err := contractRegistry.GetArtifact(step.Deploy.Contract)
+		if err != nil {
+			return stacktrace.Propagate(err, "Contract '%v' isn't in the contract registry", step.Deploy.Contract)
+		}
+		if _, err := convertToAbiValues(artifact.ABI.Constructor.Inputs, step.Deploy.Args, resolveAddress); err != nil {
+			return stacktrace.Propagate(err, "Invalid constructor args")
+		}
+		alias := step.Deploy.getAlias()
+		if knownNames[alias] {
+			return stacktrace.NewError("Name '%v' is already used by an account or another contract; set a different one with 'as'", alias)
+		}
+		knownNames[alias] = true
+		deployedArtifacts[alias] = artifact
+		return validateTransactionOptions(step.Deploy.TransactionOptions, alias, accountNames, deployedArtifacts, resolveAddress)
+	case step.Transact != nil:
+		method, err := getDeployedMethod(step.Transact.Contract, step.Transact.Method, deployedArtifacts)
+		if err != nil {
+			return err
+		}
+		if _, err := convertToAbiValues(method.Inputs, step.Transact.Args, resolveAddress); err != nil {
+			return stacktrace.Propagate(err, "Invalid args")
+		}
+		return validateTransactionOptions(step.Transact.TransactionOptions, step.Transact.Contract, accountNames, deployedArtifacts, resolveAddress)
+	case step.Call != nil:
+		method, err := getDeployedMethod(step.Call.Contract, step.Call.Method, deployedArtifacts)
+		if err != nil {
+			return err
+		}
+		if _, err := convertToAbiValues(method.Inputs, step.Call.Args, resolveAddress); err != nil {
+			return stacktrace.Propagate(err, "Invalid args")
+		}
+		if step.Call.From != "" && !accountNames[step.Call.From] {
+			return stacktrace.NewError("Account '%v' isn't declared in the scenario's accounts", step.Call.From)
+		}
+		if step.Call.Expect != nil {
+			if step.Call.ExpectRevert != nil {
+				return stacktrace.NewError("A call can't expect both return values and a revert")
+			}
+			if _, err := convertToAbiValues(method.Outputs, step.Call.Expect, resolveAddress); err != nil {
+				return stacktrace.Propagate(err, "Invalid expected return values")
+			}
+		}
+		return nil
+	case step.Wait != nil:
+		return nil
+	case step.AssertBalance != nil:
+		assertion := step.AssertBalance
+		if !strings.HasPrefix(assertion.Of, hexPrefix) && !knownNames[assertion.Of] {
+			return stacktrace.NewError("'%v' is neither a hex address nor the name of an account or contract in the scenario", assertion.Of)
+		}
+		if assertion.Equals == "" && assertion.AtLeast == "" && assertion.AtMost == "" {
+			return stacktrace.NewError("At least one of equals, atLeast, or atMost must be given")
+		}
+		for _, bound := range []string{assertion.Equals, assertion.AtLeast, assertion.AtMost} {
+			if bound == "" {
+				continue
+			}
+			if _, err := parseBigInt(bound); err != nil {
+				return stacktrace.Propagate(err, "Invalid balance bound")
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+func validateTransactionOptions(
+		options TransactionOptions,
+		targetContract string,
+		accountNames map[string]bool,
+		deployedArtifacts map[string]*contract_registry.ContractArtifact,
+		resolveAddress addressResolver) error {
+	if options.From != "" && !accountNames[options.From] {
+		return stacktrace.NewError("Account '%v' isn't declared in the scenario's accounts", options.From)
+	}
+	if options.Value != "" {
+		if _, err := parseBigInt(options.Value); err != nil {
+			return stacktrace.Propagate(err, "Invalid value")
+		}
+	}
+	if options.ExpectRevert != nil && len(options.ExpectEvents) > 0 {
+		return stacktrace.NewError("Reverted transactions emit no events, so a transaction can't expect both")
+	}
+	for _, expectedEvent := range options.ExpectEvents {
+		emitter := expectedEvent.Contract
+		if emitter == "" {
+			emitter = targetContract
+		}
+		artifact, found := deployedArtifacts[emitter]
+		if !found {
+			return stacktrace.NewError("No contract named '%v' has been deployed by this point in the scenario", emitter)
+		}
+		event, found := artifact.ABI.Events[expectedEvent.Event]
+		if !found {
+			return stacktrace.NewError("Contract '%v' has no event '%v'", artifact.Name, expectedEvent.Event)
+		}
+		for argName, argValue := range expectedEvent.Args {
+			argument, found := getEventArgument(event, argName)
+			if !found {
+				return stacktrace.NewError("Event '%v' has no argument '%v'", event.Name, argName)
+			}
+			if _, err := convertExpectedEventArg(argument, argValue, resolveAddress); err != nil {
+				return stacktrace.Propagate(err, "Invalid expected value for argument '%v' of event '%v'", argName, event.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func getDeployedMethod(contractName string, methodName string, deployedArtifacts map[string]*contract_registry.ContractArtifact) (abi.Method, error) {
+	artifact, found := deployedArtifacts[contractName]
+	if !found {
+		return abi.Method{}, stacktrace.NewError("No contract named '%v' has been deployed by this point in the scenario", contractName)
+	}
+	method, found := artifact.ABI.Methods[methodName]
+	if !found {
+		return abi.Method{}, stacktrace.NewError("Contract '%v' has no method '%v'", artifact.Name, methodName)
+	}
+	return method, nil
+}
+
+func getEventArgument(event abi.Event, argName string) (abi.Argument, bool) {
+	for _, argument := range event.Inputs {
+		if argument.Name == argName {
+			return argument, true
+		}
+	}
+	return abi.Argument{}, false
+}
+
+func convertExpectedEventArg(argument abi.Argument, value interface{}, resolveAddress addressResolver) (interface{}, error) {
+	if argument.Indexed {
+		return convertToIndexedTopicValue(argument.Type, value, resolveAddress)
+	}
+	return convertToAbiValue(argument.Type, value, resolveAddress)
+}
+
+func (step DeployStep) getAlias() string {
+	if step.As != "" {
+		return step.As
+	}
+	return step.Contract
+}
+
+// Short description of the step for the log and errors
+func (step ScenarioStep) describe() string {
+	if step.Description != "" {
+		return step.Description
+	}
+	switch {
+	case step.Deploy != nil:
+		return "deploy " + step.Deploy.getAlias()
+	case step.Transact != nil:
+		return "transact " + step.Transact.Contract + "." + step.Transact.Method
+	case step.Call != nil:
+		return "call " + step.Call.Contract + "." + step.Call.Method
+	case step.Wait != nil:
+		return "wait"
+	case step.AssertBalance != nil:
+		return "assert balance of " + step.AssertBalance.Of
+	}
+	return "no action"
+}
@@ -0,0 +1,468 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// How long a transaction may take to be accepted by every node before the scenario fails
+	transactionAcceptanceTimeout = 30 * time.Second
+
+	// Transactions expected to revert fail gas estimation, so they're sent with this limit unless the step gives one
+	defaultRevertingGasLimit = 1000000
+
+	deployMethodPrefix = "Deploy"
+)
+
+// A transaction whose expectations haven't been checked yet
+type sentTransaction struct {
+	stepDescription string
+
+	tx *types.Transaction
+
+	options TransactionOptions
+
+	// The scenario's name for the contract the transaction was sent to (or deployed), which is the default emitter of
+	//  expected events
+	targetContract string
+}
+
+// An event decoded from a receipt's logs
+type emittedEvent struct {
+	contract string
+
+	name string
+
+	args map[string]interface{}
+}
+
+// Holds the state of one scenario run; not safe for concurrent use
+type scenarioRunner struct {
+	scenario *Scenario
+
+	backend networks_impl.SmartContractBackend
+
+	contractRegistry *contract_registry.ContractRegistry
+
+	transactorsByAccount map[string]*networks_impl.NonceManagingTransactor
+
+	// Addresses of both accounts and deployed contracts, by the scenario's names for them
+	addressesByName map[string]common.Address
+
+	contractsByName map[string]*contract_registry.DynamicContract
+
+	// Transactions sent with "noWait", in the order they were sent
+	pendingTransactions []sentTransaction
+}
+
+func newScenarioRunner(
+		scenario *Scenario,
+		backend networks_impl.SmartContractBackend,
+		contractRegistry *contract_registry.ContractRegistry) *scenarioRunner {
+	return &scenarioRunner{
+		scenario:             scenario,
+		backend:              backend,
+		contractRegistry:     contractRegistry,
+		transactorsByAccount: map[string]*networks_impl.NonceManagingTransactor{},
+		addressesByName:      map[string]common.Address{},
+		contractsByName:      map[string]*contract_registry.DynamicContract{},
+		pendingTransactions:  []sentTransaction{},
+	}
+}
+
+// Funds the scenario's accounts, then runs its steps in order, stopping at the first step that fails
+func (runner *scenarioRunner) run(ctx context.Context) error {
+	defaultTransactor := runner.backend.GetNonceManagingTransactor()
+	runner.transactorsByAccount[defaultAccountName] = defaultTransactor
+	runner.addressesByName[defaultAccountName] = defaultTransactor.GetAddress()
+	if len(runner.scenario.Accounts) > 0 {
+		logrus.Infof("Funding accounts %v...", runner.scenario.Accounts)
+		accounts, err := runner.backend.GetFundedAccounts(ctx, runner.scenario.Accounts...)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred funding the scenario's accounts")
+		}
+		for _, account := range accounts {
+			transactor, err := runner.backend.NewAccountTransactor(account)
+			if err != nil {
+				return stacktrace.Propagate(err, "An error occurred creating a transactor for account '%v'", account.Name)
+			}
+			runner.transactorsByAccount[account.Name] = transactor
+			runner.addressesByName[account.Name] = account.Address
+		}
+		logrus.Info("Accounts funded")
+	}
+
+	numSteps := len(runner.scenario.Steps)
+	for i, step := range runner.scenario.Steps {
+		description := step.describe()
+		logrus.Infof("Step %v/%v: %v...", i + 1, numSteps, description)
+		if err := runner.runStep(ctx, step, description); err != nil {
+			return stacktrace.Propagate(err, "Step %v (%v) failed", i + 1, description)
+		}
+	}
+	// Transactions that no step waited for must still meet their expectations
+	if err := runner.waitForPendingTransactions(ctx); err != nil {
+		return stacktrace.Propagate(err, "A transaction sent without waiting didn't meet its expectations")
+	}
+	return nil
+}
+
+func (runner *scenarioRunner) runStep(ctx context.Context, step ScenarioStep, description string) error {
+	switch {
+	case step.Deploy != nil:
+		return runner.deploy(ctx, *step.Deploy, description)
+	case step.Transact != nil:
+		return runner.transact(ctx, *step.Transact, description)
+	case step.Call != nil:
+		return runner.call(ctx, *step.Call)
+	case step.Wait != nil:
+		return runner.waitForPendingTransactions(ctx)
+	case step.AssertBalance != nil:
+		return runner.assertBalance(ctx, *step.AssertBalance)
+	}
+	return stacktrace.NewError("Step has no action")
+}
+
+func (runner *scenarioRunner) deploy(ctx context.Context, step DeployStep, description string) error {
+	artifact, err := runner.contractRegistry.GetArtifact(step.Contract)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the artifact for contract '%v'", step.Contract)
+	}
+	args, err := convertToAbiValues(artifact.ABI.Constructor.Inputs, step.Args, runner.resolveAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred converting the constructor args")
+	}
+	transactor := runner.transactorsByAccount[getAccountName(step.From)]
+
+	var contract *contract_registry.DynamicContract
+	tx, err := transactor.Transact(ctx, artifact.Name, deployMethodPrefix + artifact.Name, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		applyTransactionOptions(opts, step.TransactionOptions)
+		var tx *types.Transaction
+		var err error
+		_, tx, contract, err = runner.contractRegistry.Deploy(opts, runner.backend.GetClient(), artifact.Name, args...)
+		return tx, err
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred sending the deployment transaction")
+	}
+	// The address is known as soon as the transaction is sent, so later steps can refer to the contract even if this
+	//  step doesn't wait
+	alias := step.getAlias()
+	runner.contractsByName[alias] = contract
+	runner.addressesByName[alias] = contract.GetAddress()
+
+	return runner.handleSentTransaction(ctx, sentTransaction{
+		stepDescription: description,
+		tx:              tx,
+		options:         step.TransactionOptions,
+		targetContract:  alias,
+	})
+}
+
+func (runner *scenarioRunner) transact(ctx context.Context, step TransactStep, description string) error {
+	contract := runner.contractsByName[step.Contract]
+	method := contract.GetArtifact().ABI.Methods[step.Method]
+	args, err := convertToAbiValues(method.Inputs, step.Args, runner.resolveAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred converting the args")
+	}
+	transactor := runner.transactorsByAccount[getAccountName(step.From)]
+
+	tx, err := transactor.Transact(ctx, contract.GetName(), step.Method, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		applyTransactionOptions(opts, step.TransactionOptions)
+		return contract.Transact(opts, step.Method, args...)
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred sending the transaction")
+	}
+	return runner.handleSentTransaction(ctx, sentTransaction{
+		stepDescription: description,
+		tx:              tx,
+		options:         step.TransactionOptions,
+		targetContract:  step.Contract,
+	})
+}
+
+func (runner *scenarioRunner) call(ctx context.Context, step CallStep) error {
+	contract := runner.contractsByName[step.Contract]
+	method := contract.GetArtifact().ABI.Methods[step.Method]
+	args, err := convertToAbiValues(method.Inputs, step.Args, runner.resolveAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred converting the args")
+	}
+	callOpts := &bind.CallOpts{
+		Context: ctx,
+		From:    runner.addressesByName[getAccountName(step.From)],
+	}
+	results, err := contract.Call(callOpts, step.Method, args...)
+	if step.ExpectRevert != nil {
+		if err == nil {
+			return stacktrace.NewError("Expected the call to revert, but it returned %v", formatAbiValue(results))
+		}
+		return checkRevertReason(networks_impl.GetCallRevertReason(err), *step.ExpectRevert)
+	}
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred calling '%v.%v'", step.Contract, step.Method)
+	}
+	if step.Expect == nil {
+		return nil
+	}
+
+	expectedResults, err := convertToAbiValues(method.Outputs, step.Expect, runner.resolveAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred converting the expected return values")
+	}
+	for i, expected := range expectedResults {
+		if formatAbiValue(expected) != formatAbiValue(results[i]) {
+			return stacktrace.NewError(
+				"Expected return value %v ('%v') of '%v.%v' to be %v, but was %v",
+				i,
+				method.Outputs[i].Name,
+				step.Contract,
+				step.Method,
+				formatAbiValue(expected),
+				formatAbiValue(results[i]))
+		}
+	}
+	return nil
+}
+
+func (runner *scenarioRunner) assertBalance(ctx context.Context, step AssertBalanceStep) error {
+	address, err := convertToAbiValue(abi.Type{T: abi.AddressTy}, step.Of, runner.resolveAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the address of '%v'", step.Of)
+	}
+	balance, err := runner.backend.GetClient().BalanceAt(ctx, address.(common.Address), nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the balance of '%v'", step.Of)
+	}
+	logrus.Infof("Balance of '%v' is %v wei", step.Of, balance)
+
+	// Bounds were checked when the scenario was loaded
+	if step.Equals != "" {
+		if bound, _ := parseBigInt(step.Equals); balance.Cmp(bound) != 0 {
+			return stacktrace.NewError("Expected the balance of '%v' to be %v wei, but was %v wei", step.Of, bound, balance)
+		}
+	}
+	if step.AtLeast != "" {
+		if bound, _ := parseBigInt(step.AtLeast); balance.Cmp(bound) < 0 {
+			return stacktrace.NewError("Expected the balance of '%v' to be at least %v wei, but was %v wei", step.Of, bound, balance)
+		}
+	}
+	if step.AtMost != "" {
+		if bound, _ := parseBigInt(step.AtMost); balance.Cmp(bound) > 0 {
+			return stacktrace.NewError("Expected the balance of '%v' to be at most %v wei, but was %v wei", step.Of, bound, balance)
+		}
+	}
+	return nil
+}
+
+func (runner *scenarioRunner) handleSentTransaction(ctx context.Context, sent sentTransaction) error {
+	if sent.options.NoWait {
+		runner.pendingTransactions = append(runner.pendingTransactions, sent)
+		return nil
+	}
+	return runner.checkTransaction(ctx, sent)
+}
+
+func (runner *scenarioRunner) waitForPendingTransactions(ctx context.Context) error {
+	pendingTransactions := runner.pendingTransactions
+	runner.pendingTransactions = []sentTransaction{}
+	for _, sent := range pendingTransactions {
+		if err := runner.checkTransaction(ctx, sent); err != nil {
+			return stacktrace.Propagate(err, "The transaction from step '%v' didn't meet its expectations", sent.stepDescription)
+		}
+	}
+	return nil
+}
+
+// Waits for the transaction to be accepted, then checks that it reverted or emitted events as expected
+func (runner *scenarioRunner) checkTransaction(ctx context.Context, sent sentTransaction) error {
+	txHash := sent.tx.Hash()
+	waitCtx, cancelFunc := context.WithTimeout(ctx, transactionAcceptanceTimeout)
+	defer cancelFunc()
+	receipt, err := runner.backend.WaitForTransactionAccepted(waitCtx, txHash)
+	if err != nil {
+		revertedErr, isReverted := stacktrace.RootCause(err).(*networks_impl.TransactionRevertedError)
+		if !isReverted {
+			return stacktrace.Propagate(
+				err,
+				"Transaction '%v' wasn't successfully accepted by every node within %v",
+				txHash.Hex(),
+				transactionAcceptanceTimeout)
+		}
+		if sent.options.ExpectRevert == nil {
+			return stacktrace.Propagate(err, "Transaction '%v' reverted unexpectedly", txHash.Hex())
+		}
+		return checkRevertReason(revertedErr.Reason, *sent.options.ExpectRevert)
+	}
+	if sent.options.ExpectRevert != nil {
+		return stacktrace.NewError("Expected transaction '%v' to revert, but it succeeded", txHash.Hex())
+	}
+	if len(sent.options.ExpectEvents) == 0 {
+		return nil
+	}
+
+	emittedEvents, err := runner.decodeEvents(receipt)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred decoding the events emitted by transaction '%v'", txHash.Hex())
+	}
+	if err := runner.checkEvents(emittedEvents, sent.options.ExpectEvents, sent.targetContract); err != nil {
+		return stacktrace.Propagate(err, "Transaction '%v' didn't emit the expected events", txHash.Hex())
+	}
+	return nil
+}
+
+// Decodes the logs emitted by contracts that the scenario deployed; other logs are skipped, since there's no ABI to
+//  decode them with
+func (runner *scenarioRunner) decodeEvents(receipt *types.Receipt) ([]emittedEvent, error) {
+	contractNamesByAddress := map[common.Address]string{}
+	for name, contract := range runner.contractsByName {
+		contractNamesByAddress[contract.GetAddress()] = name
+	}
+
+	result := []emittedEvent{}
+	for _, log := range receipt.Logs {
+		contractName, found := contractNamesByAddress[log.Address]
+		if !found || len(log.Topics) == 0 {
+			continue
+		}
+		contractAbi := runner.contractsByName[contractName].GetArtifact().ABI
+		event, err := contractAbi.EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+		args := map[string]interface{}{}
+		if len(log.Data) > 0 {
+			if err := event.Inputs.NonIndexed().UnpackIntoMap(args, log.Data); err != nil {
+				return nil, stacktrace.Propagate(err, "An error occurred unpacking the data of event '%v' from '%v'", event.Name, contractName)
+			}
+		}
+		indexedArgs := abi.Arguments{}
+		for _, argument := range event.Inputs {
+			if argument.Indexed {
+				indexedArgs = append(indexedArgs, argument)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(args, indexedArgs, log.Topics[1:]); err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred parsing the topics of event '%v' from '%v'", event.Name, contractName)
+		}
+		result = append(result, emittedEvent{
+			contract: contractName,
+			name:     event.Name,
+			args:     args,
+		})
+	}
+	return result, nil
+}
+
+// The expected events must appear in the emitted events in order, but other events may come between them
+func (runner *scenarioRunner) checkEvents(emittedEvents []emittedEvent, expectedEvents []ExpectedEvent, targetContract string) error {
+	nextEmittedIdx := 0
+	for i, expected := range expectedEvents {
+		expectedContract := expected.Contract
+		if expectedContract == "" {
+			expectedContract = targetContract
+		}
+		event := runner.contractsByName[expectedContract].GetArtifact().ABI.Events[expected.Event]
+		expectedArgs := map[string]string{}
+		for argName, argValue := range expected.Args {
+			argument, _ := getEventArgument(event, argName)
+			converted, err := convertExpectedEventArg(argument, argValue, runner.resolveAddress)
+			if err != nil {
+				return stacktrace.Propagate(err, "An error occurred converting the expected value of argument '%v' of event '%v'", argName, expected.Event)
+			}
+			expectedArgs[argName] = formatAbiValue(converted)
+		}
+
+		found := false
+		for nextEmittedIdx < len(emittedEvents) && !found {
+			emitted := emittedEvents[nextEmittedIdx]
+			nextEmittedIdx++
+			found = emitted.contract == expectedContract && emitted.name == expected.Event && eventArgsMatch(emitted, expectedArgs)
+		}
+		if !found {
+			return stacktrace.NewError(
+				"Expected event %v ('%v.%v' with args %v) wasn't emitted in order; the emitted events were: %v",
+				i + 1,
+				expectedContract,
+				expected.Event,
+				expectedArgs,
+				formatEmittedEvents(emittedEvents))
+		}
+	}
+	return nil
+}
+
+func (runner *scenarioRunner) resolveAddress(name string) (common.Address, bool) {
+	address, found := runner.addressesByName[name]
+	return address, found
+}
+
+func applyTransactionOptions(opts *bind.TransactOpts, options TransactionOptions) {
+	if options.Value != "" {
+		// Checked when the scenario was loaded
+		opts.Value, _ = parseBigInt(options.Value)
+	}
+	if options.GasLimit != 0 {
+		opts.GasLimit = options.GasLimit
+	} else if options.ExpectRevert != nil && opts.GasLimit == 0 {
+		opts.GasLimit = defaultRevertingGasLimit
+	}
+}
+
+func checkRevertReason(reason string, expectedReasonSubstring string) error {
+	if !strings.Contains(reason, expectedReasonSubstring) {
+		return stacktrace.NewError("Expected a revert reason containing '%v', but the reason was '%v'", expectedReasonSubstring, reason)
+	}
+	logrus.Infof("Reverted as expected, with reason '%v'", reason)
+	return nil
+}
+
+func eventArgsMatch(emitted emittedEvent, expectedArgs map[string]string) bool {
+	for argName, expectedValue := range expectedArgs {
+		if formatAbiValue(emitted.args[argName]) != expectedValue {
+			return false
+		}
+	}
+	return true
+}
+
+func formatEmittedEvents(events []emittedEvent) string {
+	if len(events) == 0 {
+		return "none"
+	}
+	formattedEvents := []string{}
+	for _, event := range events {
+		formattedArgs := []string{}
+		for argName, argValue := range event.args {
+			formattedArgs = append(formattedArgs, argName + "=" + formatAbiValue(argValue))
+		}
+		sort.Strings(formattedArgs)
+		formattedEvents = append(formattedEvents, event.contract + "." + event.name + "(" + strings.Join(formattedArgs, ", ") + ")")
+	}
+	return strings.Join(formattedEvents, "; ")
+}
+
+func getAccountName(from string) string {
+	if from == "" {
+		return defaultAccountName
+	}
+	return from
+}
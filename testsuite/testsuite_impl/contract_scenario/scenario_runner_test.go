@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"testing"
+)
+
+var (
+	emitterAddress      = common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	otherEmitterAddress = common.HexToAddress("0x00000000000000000000000000000000000000e2")
+	unknownAddress      = common.HexToAddress("0x00000000000000000000000000000000000000ff")
+)
+
+func TestDecodeEvents(t *testing.T) {
+	runner := newEventTestRunner(t)
+	emittedEvents, err := runner.decodeEvents(newEventTestReceipt(t, runner))
+	if err != nil {
+		t.Fatalf("An error occurred decoding the events: %v", err)
+	}
+
+	// Logs from contracts the scenario didn't deploy, with unknown topics, or without topics are skipped
+	expectedEvents := []struct {
+		contract string
+		name     string
+		args     map[string]string
+	}{
+		{
+			contract: "emitter",
+			name:     "Transfer",
+			args: map[string]string{
+				"from":  formatAbiValue(aliceAddress),
+				"to":    formatAbiValue(bobAddress),
+				"value": "5",
+			},
+		},
+		{
+			contract: "other",
+			name:     "Labeled",
+			args: map[string]string{
+				"label": crypto.Keccak256Hash([]byte("tag")).Hex(),
+				"note":  `"a note"`,
+			},
+		},
+		{
+			contract: "emitter",
+			name:     "Transfer",
+			args: map[string]string{
+				"from":  formatAbiValue(bobAddress),
+				"to":    formatAbiValue(aliceAddress),
+				"value": "7",
+			},
+		},
+	}
+	if len(emittedEvents) != len(expectedEvents) {
+		t.Fatalf("Expected %v decoded events, but got %v: %v", len(expectedEvents), len(emittedEvents), formatEmittedEvents(emittedEvents))
+	}
+	for i, expected := range expectedEvents {
+		emitted := emittedEvents[i]
+		if emitted.contract != expected.contract || emitted.name != expected.name {
+			t.Errorf("Expected event %v to be '%v.%v', but was '%v.%v'", i, expected.contract, expected.name, emitted.contract, emitted.name)
+			continue
+		}
+		if len(emitted.args) != len(expected.args) {
+			t.Errorf("Expected event %v to have %v args, but it had %v", i, len(expected.args), len(emitted.args))
+		}
+		if !eventArgsMatch(emitted, expected.args) {
+			t.Errorf("Expected event %v to have args %v, but got: %v", i, expected.args, formatEmittedEvents([]emittedEvent{emitted}))
+		}
+	}
+}
+
+func TestCheckEvents(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		expectedEvents []ExpectedEvent
+
+		isErrExpected bool
+	}{
+		{
+			name:           "Nothing expected",
+			expectedEvents: []ExpectedEvent{},
+		},
+		{
+			name: "Unlisted args match any value",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Transfer", Contract: "", Args: nil},
+			},
+		},
+		{
+			name: "Args given by account name and as strings",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Transfer", Contract: "", Args: map[string]interface{}{"from": "alice", "to": "bob", "value": "5"}},
+				{Event: "Transfer", Contract: "emitter", Args: map[string]interface{}{"from": "bob", "value": "0x7"}},
+			},
+		},
+		{
+			name: "Other events may come between expected ones",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Transfer", Contract: "", Args: map[string]interface{}{"value": "7"}},
+			},
+		},
+		{
+			name: "Indexed string matched by its hash",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Transfer", Contract: "", Args: nil},
+				{Event: "Labeled", Contract: "other", Args: map[string]interface{}{"label": "tag", "note": "a note"}},
+				{Event: "Transfer", Contract: "", Args: nil},
+			},
+		},
+		{
+			name: "Out of order",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Transfer", Contract: "", Args: map[string]interface{}{"value": "7"}},
+				{Event: "Transfer", Contract: "", Args: map[string]interface{}{"value": "5"}},
+			},
+			isErrExpected: true,
+		},
+		{
+			name: "Wrong arg value",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Transfer", Contract: "", Args: map[string]interface{}{"from": "bob", "to": "bob"}},
+			},
+			isErrExpected: true,
+		},
+		{
+			name: "Wrong indexed string",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Labeled", Contract: "other", Args: map[string]interface{}{"label": "untagged"}},
+			},
+			isErrExpected: true,
+		},
+		{
+			name: "Emitted by a different contract",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Labeled", Contract: "", Args: nil},
+			},
+			isErrExpected: true,
+		},
+		{
+			name: "Expected more times than emitted",
+			expectedEvents: []ExpectedEvent{
+				{Event: "Transfer", Contract: "", Args: nil},
+				{Event: "Transfer", Contract: "", Args: nil},
+				{Event: "Transfer", Contract: "", Args: nil},
+			},
+			isErrExpected: true,
+		},
+	}
+
+	runner := newEventTestRunner(t)
+	emittedEvents, err := runner.decodeEvents(newEventTestReceipt(t, runner))
+	if err != nil {
+		t.Fatalf("An error occurred decoding the events: %v", err)
+	}
+	for _, testCase := range testCases {
+		err := runner.checkEvents(emittedEvents, testCase.expectedEvents, "emitter")
+		if testCase.isErrExpected && err == nil {
+			t.Errorf("%v: expected the events not to match, but they did", testCase.name)
+		}
+		if !testCase.isErrExpected && err != nil {
+			t.Errorf("%v: expected the events to match, but got an error: %v", testCase.name, err)
+		}
+	}
+}
+
+// Has two instances of the test contract deployed, as 'emitter' and 'other', and accounts 'alice' and 'bob'
+func newEventTestRunner(t *testing.T) *scenarioRunner {
+	contractRegistry := loadTestContractRegistry(t)
+	runner := newScenarioRunner(newValidTestScenario(), nil, contractRegistry)
+	for name, address := range map[string]common.Address{"emitter": emitterAddress, "other": otherEmitterAddress} {
+		contract, err := contractRegistry.Bind(testContractName, address, nil)
+		if err != nil {
+			t.Fatalf("An error occurred binding the test contract as '%v': %v", name, err)
+		}
+		runner.contractsByName[name] = contract
+		runner.addressesByName[name] = address
+	}
+	runner.addressesByName["alice"] = aliceAddress
+	runner.addressesByName["bob"] = bobAddress
+	return runner
+}
+
+// In order: Transfer(alice, bob, 5) from emitter, a Transfer from a contract the scenario didn't deploy, a log with an
+//  unknown topic, Labeled("tag", "a note") from other, a log without topics, and Transfer(bob, alice, 7) from emitter
+func newEventTestReceipt(t *testing.T, runner *scenarioRunner) *types.Receipt {
+	contractAbi := runner.contractsByName["emitter"].GetArtifact().ABI
+	transferEvent := contractAbi.Events["Transfer"]
+	labeledEvent := contractAbi.Events["Labeled"]
+	return &types.Receipt{
+		Logs: []*types.Log{
+			newTestLog(t, emitterAddress, transferEvent, []interface{}{aliceAddress, bobAddress}, big.NewInt(5)),
+			newTestLog(t, unknownAddress, transferEvent, []interface{}{aliceAddress, bobAddress}, big.NewInt(6)),
+			{Address: emitterAddress, Topics: []common.Hash{crypto.Keccak256Hash([]byte("Unknown()"))}, Data: nil},
+			newTestLog(t, otherEmitterAddress, labeledEvent, []interface{}{"tag"}, "a note"),
+			{Address: emitterAddress, Topics: nil, Data: nil},
+			newTestLog(t, emitterAddress, transferEvent, []interface{}{bobAddress, aliceAddress}, big.NewInt(7)),
+		},
+	}
+}
+
+func newTestLog(t *testing.T, address common.Address, event abi.Event, indexedValues []interface{}, nonIndexedValues ...interface{}) *types.Log {
+	topics := []common.Hash{event.ID}
+	for _, value := range indexedValues {
+		valueTopics, err := abi.MakeTopics([]interface{}{value})
+		if err != nil {
+			t.Fatalf("An error occurred making the topic for value '%v' of event '%v': %v", value, event.Name, err)
+		}
+		topics = append(topics, valueTopics[0][0])
+	}
+	data, err := event.Inputs.NonIndexed().Pack(nonIndexedValues...)
+	if err != nil {
+		t.Fatalf("An error occurred packing the data of event '%v': %v", event.Name, err)
+	}
+	return &types.Log{
+		Address: address,
+		Topics:  topics,
+		Data:    data,
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario
+
+import (
+	"context"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	// Given to every scenario test on top of the scenario's own tags, so that they can be selected as a group
+	scenarioTag = "scenario"
+)
+
+// Runs a scenario loaded from a file
+type ScenarioTest struct {
+	scenario *Scenario
+	networkConfig networks_impl.SmartContractAvalancheNetworkConfig
+	gasReportConfig networks_impl.GasReportConfig
+	contractRegistry *contract_registry.ContractRegistry
+	setupTimeout time.Duration
+	runTimeout time.Duration
+}
+
+func NewScenarioTest(scenario *Scenario, dependencies test_catalog.TestDependencies) *ScenarioTest {
+	return &ScenarioTest{
+		scenario:         scenario,
+		networkConfig:    dependencies.NetworkConfig,
+		gasReportConfig:  dependencies.GasReportConfig,
+		contractRegistry: dependencies.ContractRegistry,
+		setupTimeout:     dependencies.SetupTimeout,
+		runTimeout:       dependencies.RunTimeout,
+	}
+}
+
+// Loads the scenarios in the directory and registers a test for each one in the test catalog, named after the
+//  scenario
+func RegisterScenarioTests(dirpath string, contractRegistry *contract_registry.ContractRegistry) error {
+	scenarios, err := LoadScenarios(dirpath, contractRegistry)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred loading the scenarios in '%v'", dirpath)
+	}
+	for _, scenario := range scenarios {
+		// Captured by the constructor below, so must be a fresh variable on each iteration
+		scenario := scenario
+		metadata := test_catalog.TestMetadata{
+			Tags:       append([]string{scenarioTag}, scenario.Tags...),
+			RunTimeout: time.Duration(scenario.RunTimeoutSeconds) * time.Second,
+		}
+		err := test_catalog.TryRegister(scenario.Name, metadata, func(dependencies test_catalog.TestDependencies) testsuite.Test {
+			return NewScenarioTest(scenario, dependencies)
+		})
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred registering a test for scenario '%v' from '%v'", scenario.Name, scenario.sourceFilepath)
+		}
+	}
+	return nil
+}
+
+func (test ScenarioTest) Configure(builder *testsuite.TestConfigurationBuilder) {
+	builder.WithSetupTimeoutSeconds(uint32(test.setupTimeout.Seconds())).WithRunTimeoutSeconds(uint32(test.runTimeout.Seconds()))
+}
+
+func (test *ScenarioTest) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), test.setupTimeout - test_catalog.TimeoutBuffer)
+	defer cancelFunc()
+
+	backend, err := networks_impl.SetUpSmartContractBackend(ctx, test.networkConfig, networkCtx)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred setting up the smart contract backend")
+	}
+	return backend, nil
+}
+
+func (test ScenarioTest) Run(uncastedNetwork networks.Network) error {
+	// Necessary because Go doesn't have generics
+	network, ok := uncastedNetwork.(networks_impl.SmartContractBackend)
+	if !ok {
+		return stacktrace.NewError("Couldn't cast the generic network to the appropriate type")
+	}
+	defer func() {
+		if err := networks_impl.CloseSmartContractBackend(network); err != nil {
+			logrus.Warnf("An error occurred closing the smart contract backend after the test: %v", err)
+		}
+	}()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), test.runTimeout - test_catalog.TimeoutBuffer)
+	defer cancelFunc()
+
+	return test.RunAgainstBackend(ctx, network)
+}
+
+// Separate from Run so that scenarios can also run against a SimulatedSmartContractBackend in a plain 'go test'
+func (test ScenarioTest) RunAgainstBackend(ctx context.Context, network networks_impl.SmartContractBackend) error {
+	runner := newScenarioRunner(test.scenario, network, test.contractRegistry)
+	if err := runner.run(ctx); err != nil {
+		return stacktrace.Propagate(err, "Scenario '%v' from '%v' failed", test.scenario.Name, test.scenario.sourceFilepath)
+	}
+	if err := test.gasReportConfig.FinishReport(test.scenario.Name, network.GetGasUsageRecorder()); err != nil {
+		return stacktrace.Propagate(err, "An error occurred finishing the gas usage report")
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	// Holds a hand-written ABI for an EventEmitter contract, since neither sample contract declares any events; it has
+	//  no bytecode, so it can be validated against and bound to, but not deployed
+	testContractArtifactsDirpath = "testdata/artifacts"
+
+	testContractName = "EventEmitter"
+)
+
+func TestParseScenarioFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		contents string
+
+		// Ignored if an error is expected
+		expectedFirstArg string
+		isErrExpected    bool
+	}{
+		{
+			name:     "JSON keeps large integers exact",
+			filename: "scenario.json",
+			contents: `{"name": "parsed", "steps": [{"deploy": {"contract": "EventEmitter", "args": [123456789012345678901234567890]}}]}`,
+			expectedFirstArg: "123456789012345678901234567890",
+		},
+		{
+			name:     "YAML",
+			filename: "scenario.yaml",
+			contents: "name: parsed\nsteps:\n  - deploy:\n      contract: EventEmitter\n      args:\n        - \"0xff\"\n",
+			expectedFirstArg: "0xff",
+		},
+		{
+			name:     "YML extension in upper case",
+			filename: "scenario.YML",
+			contents: "name: parsed\nsteps:\n  - deploy:\n      contract: EventEmitter\n      args: [7]\n",
+			expectedFirstArg: "7",
+		},
+		{
+			name:          "Misspelled field",
+			filename:      "scenario.json",
+			contents:      `{"name": "parsed", "steps": [{"deploy": {"contract": "EventEmitter", "expectEvent": []}}]}`,
+			isErrExpected: true,
+		},
+		{
+			name:          "Invalid YAML",
+			filename:      "scenario.yaml",
+			contents:      "name: [parsed\n",
+			isErrExpected: true,
+		},
+		{
+			name:          "Invalid JSON",
+			filename:      "scenario.json",
+			contents:      `{"name": "parsed",`,
+			isErrExpected: true,
+		},
+	}
+
+	dirpath, err := ioutil.TempDir("", "scenario-parsing-test")
+	if err != nil {
+		t.Fatalf("An error occurred creating the scenario directory: %v", err)
+	}
+	defer os.RemoveAll(dirpath)
+	for i, testCase := range testCases {
+		scenarioFilepath := filepath.Join(dirpath, fmt.Sprintf("%v-%v", i, testCase.filename))
+		if err := ioutil.WriteFile(scenarioFilepath, []byte(testCase.contents), 0644); err != nil {
+			t.Fatalf("%v: an error occurred writing the scenario file: %v", testCase.name, err)
+		}
+
+		scenario, err := parseScenarioFile(scenarioFilepath)
+		if testCase.isErrExpected {
+			if err == nil {
+				t.Errorf("%v: expected an error parsing the scenario, but got none", testCase.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: an error occurred parsing the scenario: %v", testCase.name, err)
+			continue
+		}
+		if scenario.Name != "parsed" || scenario.sourceFilepath != scenarioFilepath {
+			t.Errorf("%v: expected scenario 'parsed' from '%v', but got '%v' from '%v'", testCase.name, scenarioFilepath, scenario.Name, scenario.sourceFilepath)
+		}
+		if firstArg := fmt.Sprint(scenario.Steps[0].Deploy.Args[0]); firstArg != testCase.expectedFirstArg {
+			t.Errorf("%v: expected the first deploy arg to be '%v', but was '%v'", testCase.name, testCase.expectedFirstArg, firstArg)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	emptyRevertReason := ""
+	testCases := []struct {
+		name string
+
+		// Applied to a valid scenario
+		mutate func(scenario *Scenario)
+
+		// Empty if the scenario should be valid
+		expectedErrSubstring string
+	}{
+		{
+			name:   "Valid",
+			mutate: func(scenario *Scenario) {},
+		},
+		{
+			name:                 "Empty name",
+			mutate:               func(scenario *Scenario) { scenario.Name = " " },
+			expectedErrSubstring: "Scenario name is empty",
+		},
+		{
+			name:                 "Negative run timeout",
+			mutate:               func(scenario *Scenario) { scenario.RunTimeoutSeconds = -1 },
+			expectedErrSubstring: "Run timeout must be >= 0 seconds",
+		},
+		{
+			name:                 "No steps",
+			mutate:               func(scenario *Scenario) { scenario.Steps = nil },
+			expectedErrSubstring: "Scenario has no steps",
+		},
+		{
+			name:                 "Empty account name",
+			mutate:               func(scenario *Scenario) { scenario.Accounts = append(scenario.Accounts, "") },
+			expectedErrSubstring: "Account names may not be empty",
+		},
+		{
+			name:                 "Duplicate account",
+			mutate:               func(scenario *Scenario) { scenario.Accounts = append(scenario.Accounts, "alice") },
+			expectedErrSubstring: "Account 'alice' is declared more than once",
+		},
+		{
+			name:                 "Reserved account name",
+			mutate:               func(scenario *Scenario) { scenario.Accounts = append(scenario.Accounts, defaultAccountName) },
+			expectedErrSubstring: "uses the reserved name",
+		},
+		{
+			name:                 "Step without an action",
+			mutate:               func(scenario *Scenario) { scenario.Steps[3] = ScenarioStep{Description: "nothing"} },
+			expectedErrSubstring: "this one has 0",
+		},
+		{
+			name:                 "Step with two actions",
+			mutate:               func(scenario *Scenario) { scenario.Steps[3].AssertBalance = scenario.Steps[4].AssertBalance },
+			expectedErrSubstring: "this one has 2",
+		},
+		{
+			name:                 "Unknown contract",
+			mutate:               func(scenario *Scenario) { scenario.Steps[0].Deploy.Contract = "Missing" },
+			expectedErrSubstring: "Contract 'Missing' isn't in the contract registry",
+		},
+		{
+			name:                 "Constructor arg out of range",
+			mutate:               func(scenario *Scenario) { scenario.Steps[0].Deploy.Args = []interface{}{json.Number("256")} },
+			expectedErrSubstring: "Invalid constructor args",
+		},
+		{
+			name:                 "Contract alias taken by an account",
+			mutate:               func(scenario *Scenario) { scenario.Steps[0].Deploy.As = "alice" },
+			expectedErrSubstring: "Name 'alice' is already used",
+		},
+		{
+			name:                 "Transaction to an undeployed contract",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.Contract = "other" },
+			expectedErrSubstring: "No contract named 'other' has been deployed",
+		},
+		{
+			name:                 "Unknown method",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.Method = "burn" },
+			expectedErrSubstring: "has no method 'burn'",
+		},
+		{
+			name:                 "Wrong number of args",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.Args = []interface{}{"alice"} },
+			expectedErrSubstring: "Invalid args",
+		},
+		{
+			name:                 "Undeclared sender",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.From = "mallory" },
+			expectedErrSubstring: "Account 'mallory' isn't declared",
+		},
+		{
+			name:                 "Invalid value",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.Value = "lots" },
+			expectedErrSubstring: "Invalid value",
+		},
+		{
+			name:                 "Both a revert and events expected",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.ExpectRevert = &emptyRevertReason },
+			expectedErrSubstring: "can't expect both",
+		},
+		{
+			name:                 "Unknown event",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.ExpectEvents[0].Event = "Approval" },
+			expectedErrSubstring: "has no event 'Approval'",
+		},
+		{
+			name:                 "Event from an undeployed contract",
+			mutate:               func(scenario *Scenario) { scenario.Steps[1].Transact.ExpectEvents[0].Contract = "other" },
+			expectedErrSubstring: "No contract named 'other' has been deployed",
+		},
+		{
+			name: "Unknown event arg",
+			mutate: func(scenario *Scenario) {
+				scenario.Steps[1].Transact.ExpectEvents[0].Args["amount"] = "1000"
+			},
+			expectedErrSubstring: "Event 'Transfer' has no argument 'amount'",
+		},
+		{
+			name: "Invalid event arg value",
+			mutate: func(scenario *Scenario) {
+				scenario.Steps[1].Transact.ExpectEvents[0].Args["to"] = "mallory"
+			},
+			expectedErrSubstring: "Invalid expected value for argument 'to'",
+		},
+		{
+			name: "Indexed array event arg",
+			mutate: func(scenario *Scenario) {
+				scenario.Steps[1].Transact.ExpectEvents[0] = ExpectedEvent{
+					Event:    "Batch",
+					Contract: "",
+					Args:     map[string]interface{}{"ids": []interface{}{"1"}},
+				}
+			},
+			expectedErrSubstring: "can't be asserted on",
+		},
+		{
+			name:                 "Undeclared caller",
+			mutate:               func(scenario *Scenario) { scenario.Steps[2].Call.From = "mallory" },
+			expectedErrSubstring: "Account 'mallory' isn't declared",
+		},
+		{
+			name:                 "Call expecting both return values and a revert",
+			mutate:               func(scenario *Scenario) { scenario.Steps[2].Call.ExpectRevert = &emptyRevertReason },
+			expectedErrSubstring: "can't expect both return values and a revert",
+		},
+		{
+			name:                 "Invalid expected return value",
+			mutate:               func(scenario *Scenario) { scenario.Steps[2].Call.Expect = []interface{}{"zero"} },
+			expectedErrSubstring: "Invalid expected return values",
+		},
+		{
+			name:                 "Balance of an unknown name",
+			mutate:               func(scenario *Scenario) { scenario.Steps[4].AssertBalance.Of = "nobody" },
+			expectedErrSubstring: "'nobody' is neither a hex address nor the name",
+		},
+		{
+			name:                 "Balance without bounds",
+			mutate:               func(scenario *Scenario) { scenario.Steps[4].AssertBalance.Equals = "" },
+			expectedErrSubstring: "At least one of equals, atLeast, or atMost must be given",
+		},
+		{
+			name:                 "Invalid balance bound",
+			mutate:               func(scenario *Scenario) { scenario.Steps[4].AssertBalance.AtMost = "1e18" },
+			expectedErrSubstring: "Invalid balance bound",
+		},
+	}
+
+	contractRegistry := loadTestContractRegistry(t)
+	for _, testCase := range testCases {
+		scenario := newValidTestScenario()
+		testCase.mutate(scenario)
+		err := scenario.validate(contractRegistry)
+		if testCase.expectedErrSubstring == "" {
+			if err != nil {
+				t.Errorf("%v: expected the scenario to be valid, but got an error: %v", testCase.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%v: expected an error containing '%v', but the scenario was valid", testCase.name, testCase.expectedErrSubstring)
+			continue
+		}
+		if !strings.Contains(err.Error(), testCase.expectedErrSubstring) {
+			t.Errorf("%v: expected an error containing '%v', but got: %v", testCase.name, testCase.expectedErrSubstring, err)
+		}
+	}
+}
+
+// Built fresh for every test case, so that cases can mutate it freely
+func newValidTestScenario() *Scenario {
+	return &Scenario{
+		Name:              "validated",
+		Description:       "",
+		Tags:              nil,
+		RunTimeoutSeconds: 0,
+		Accounts:          []string{"alice"},
+		Steps: []ScenarioStep{
+			{
+				Deploy: &DeployStep{
+					Contract: testContractName,
+					As:       "emitter",
+					Args:     []interface{}{json.Number("7")},
+				},
+			},
+			{
+				Transact: &TransactStep{
+					Contract: "emitter",
+					Method:   "transfer",
+					Args:     []interface{}{"alice", "1000"},
+					TransactionOptions: TransactionOptions{
+						From: "alice",
+						ExpectEvents: []ExpectedEvent{
+							{
+								Event:    "Transfer",
+								Contract: "",
+								Args:     map[string]interface{}{"to": "alice", "value": "1000"},
+							},
+						},
+					},
+				},
+			},
+			{
+				Call: &CallStep{
+					Contract: "emitter",
+					Method:   "get",
+					Args:     nil,
+					Expect:   []interface{}{json.Number("0")},
+				},
+			},
+			{
+				Wait: &WaitStep{},
+			},
+			{
+				AssertBalance: &AssertBalanceStep{
+					Of:     "emitter",
+					Equals: "0",
+				},
+			},
+		},
+	}
+}
+
+func loadTestContractRegistry(t *testing.T) *contract_registry.ContractRegistry {
+	contractRegistry, err := contract_registry.LoadContractRegistry(testContractArtifactsDirpath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the test contract registry: %v", err)
+	}
+	return contractRegistry
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package contract_scenario_test
+
+import (
+	"context"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/testsuite_impl/contract_scenario"
+	"testing"
+)
+
+const (
+	// Relative to this package's directory, which is where 'go test' runs tests from
+	contractArtifactsDirpath = "../../../smart_contracts/artifacts"
+	scenariosDirpath         = "../../../smart_contracts/scenarios"
+)
+
+// Runs every scenario in the repo against its own in-memory chain, so that scenarios can be written and debugged
+//  without Docker or Kurtosis
+func TestScenariosOnSimulatedBackend(t *testing.T) {
+	contractRegistry, err := contract_registry.LoadContractRegistry(contractArtifactsDirpath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the contract registry: %v", err)
+	}
+	scenarios, err := contract_scenario.LoadScenarios(scenariosDirpath, contractRegistry)
+	if err != nil {
+		t.Fatalf("An error occurred loading the scenarios: %v", err)
+	}
+	if len(scenarios) == 0 {
+		t.Fatalf("No scenarios were found in '%v'", scenariosDirpath)
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.Name, func(t *testing.T) {
+			backend, networkConfig, err := networks_impl.NewSimulatedTestBackend()
+			if err != nil {
+				t.Fatalf("An error occurred creating the simulated backend: %v", err)
+			}
+			defer backend.Close()
+
+			test := contract_scenario.NewScenarioTest(scenario, test_catalog.TestDependencies{
+				NetworkConfig:    networkConfig,
+				GasReportConfig:  networks_impl.GasReportConfig{},
+				ContractRegistry: contractRegistry,
+				SetupTimeout:     0,
+				RunTimeout:       networks_impl.SimulatedTestRunTimeout,
+			})
+			ctx, cancelFunc := context.WithTimeout(context.Background(), networks_impl.SimulatedTestRunTimeout)
+			defer cancelFunc()
+			if err := test.RunAgainstBackend(ctx, backend); err != nil {
+				t.Fatalf("Scenario '%v' failed on the simulated backend: %v", scenario.Name, err)
+			}
+		})
+	}
+}
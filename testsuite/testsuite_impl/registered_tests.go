@@ -0,0 +1,12 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+package testsuite_impl
+
+// Test packages register their tests with the test catalog when they're initialized, so adding a test package to the
+//  suite only takes an import here
+import (
+	_ "github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/testsuite_impl/smart_contract_test"
+)
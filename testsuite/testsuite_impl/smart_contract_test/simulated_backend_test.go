@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2021 - present Kurtosis Technologies LLC.
+ * All Rights Reserved.
+ */
+
+// An external test package, because Go treats any package in a _test.go file whose name ends in '_test' as an external
+//  test package, so this package's own name can't be used here
+package smart_contract_test_test
+
+import (
+	"context"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/testsuite_impl/smart_contract_test"
+	"testing"
+)
+
+const (
+	// Relative to this package's directory, which is where 'go test' runs tests from
+	contractArtifactsDirpath = "../../../smart_contracts/artifacts"
+	gasBaselineFilepath      = "../../../smart_contracts/gas_baseline.json"
+)
+
+// Runs the same logic as the Kurtosis test, but against an in-memory chain, so that it can be iterated on without
+//  Docker or Kurtosis
+func TestSmartContractTestOnSimulatedBackend(t *testing.T) {
+	backend, networkConfig, err := networks_impl.NewSimulatedTestBackend()
+	if err != nil {
+		t.Fatalf("An error occurred creating the simulated backend: %v", err)
+	}
+	defer backend.Close()
+
+	contractRegistry, err := contract_registry.LoadContractRegistry(contractArtifactsDirpath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the contract registry: %v", err)
+	}
+	gasBaseline, err := networks_impl.LoadGasBaseline(gasBaselineFilepath)
+	if err != nil {
+		t.Fatalf("An error occurred loading the gas baseline: %v", err)
+	}
+	gasReportConfig := networks_impl.GasReportConfig{
+		ReportDirpath: "",
+		Baseline:      gasBaseline,
+	}
+
+	test := smart_contract_test.NewSmartContractTest(test_catalog.TestDependencies{
+		NetworkConfig:    networkConfig,
+		GasReportConfig:  gasReportConfig,
+		ContractRegistry: contractRegistry,
+		SetupTimeout:     0,
+		RunTimeout:       networks_impl.SimulatedTestRunTimeout,
+	})
+	ctx, cancelFunc := context.WithTimeout(context.Background(), networks_impl.SimulatedTestRunTimeout)
+	defer cancelFunc()
+	fixturedBackend, err := test.SetUpAgainstBackend(ctx, backend)
+	if err != nil {
+		t.Fatalf("An error occurred setting up the smart contract test on the simulated backend: %v", err)
+	}
+	if err := test.RunAgainstBackend(ctx, fixturedBackend); err != nil {
+		t.Fatalf("The smart contract test failed on the simulated backend: %v", err)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/smart_contracts/bindings"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_fixtures"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/contract_registry"
 	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/networks_impl"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
 	"github.com/palantir/stacktrace"
@@ -15,77 +18,162 @@ import (
 	"time"
 )
 
+var (
+	// The storage slot of SimpleStorage.num, which is the contract's only state variable
+	simpleStorageNumSlot = common.BigToHash(big.NewInt(0))
+)
+
 const (
-	maxNumCheckTransactionMinedRetries = 10
-	timeBetweenCheckTransactionMinedRetries = 1 * time.Second
+	testName = "smartContractTest"
+
+	// How long a transaction may take to be accepted by every node before the test fails
+	transactionAcceptanceTimeout = 30 * time.Second
+
+	gasReportName = testName
+
+	helloWorldContractName = "HelloWorld"
+	simpleStorageContractName = "SimpleStorage"
+
+	// Names the test gives its contract fixtures
+	helloWorldFixtureName = "helloWorld"
+	simpleStorageFixtureName = "simpleStorage"
 )
 
+func init() {
+	// The timeouts are left to the timeout config, so that they can be tuned from the custom params
+	metadata := test_catalog.TestMetadata{
+		Tags: []string{"smoke", "bindings", "registry"},
+	}
+	test_catalog.Register(testName, metadata, func(dependencies test_catalog.TestDependencies) testsuite.Test {
+		return NewSmartContractTest(dependencies)
+	})
+}
+
 type SmartContractTest struct {
-	avalancheImage string
+	networkConfig networks_impl.SmartContractAvalancheNetworkConfig
+	gasReportConfig networks_impl.GasReportConfig
+	contractRegistry *contract_registry.ContractRegistry
+	setupTimeout time.Duration
+	runTimeout time.Duration
 }
 
-func NewSmartContractTest(avalancheImage string) *SmartContractTest {
-	return &SmartContractTest{avalancheImage: avalancheImage}
+// The dependencies' network config is the test's own copy, so the test can adjust the suite-wide defaults (e.g. add
+//  more nodes) without affecting other tests
+func NewSmartContractTest(dependencies test_catalog.TestDependencies) *SmartContractTest {
+	return &SmartContractTest{
+		networkConfig:    dependencies.NetworkConfig,
+		gasReportConfig:  dependencies.GasReportConfig,
+		contractRegistry: dependencies.ContractRegistry,
+		setupTimeout:     dependencies.SetupTimeout,
+		runTimeout:       dependencies.RunTimeout,
+	}
 }
 
 func (test SmartContractTest) Configure(builder *testsuite.TestConfigurationBuilder) {
-	builder.WithSetupTimeoutSeconds(180).WithRunTimeoutSeconds(180)
+	builder.WithSetupTimeoutSeconds(uint32(test.setupTimeout.Seconds())).WithRunTimeoutSeconds(uint32(test.runTimeout.Seconds()))
 }
 
 func (test *SmartContractTest) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
-	network := networks_impl.NewSmartContractAvalancheNetwork(test.avalancheImage, networkCtx)
-	if err := network.SetupAvalancheNetwork(); err != nil {
-		return nil, stacktrace.Propagate(err, "An error occurred setting up the Avalanche network")
+	ctx, cancelFunc := context.WithTimeout(context.Background(), test.setupTimeout - test_catalog.TimeoutBuffer)
+	defer cancelFunc()
+
+	backend, err := networks_impl.SetUpSmartContractBackend(ctx, test.networkConfig, networkCtx)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred setting up the smart contract backend")
+	}
+	fixturedBackend, err := test.SetUpAgainstBackend(ctx, backend)
+	if err != nil {
+		// Run won't get the backend to close it
+		if closeErr := networks_impl.CloseSmartContractBackend(backend); closeErr != nil {
+			logrus.Warnf("An error occurred closing the smart contract backend after setup failed: %v", closeErr)
+		}
+		return nil, stacktrace.Propagate(err, "An error occurred setting up the test against the smart contract backend")
+	}
+	return fixturedBackend, nil
+}
+
+// Deploys the test's contract fixtures; separate from Setup so that it can also run against a
+//  SimulatedSmartContractBackend in a plain 'go test'
+func (test SmartContractTest) SetUpAgainstBackend(
+		ctx context.Context,
+		backend networks_impl.SmartContractBackend) (*contract_fixtures.FixturedSmartContractBackend, error) {
+	fixturedBackend, err := contract_fixtures.DeployContractFixtures(ctx, backend, test.contractRegistry, getContractFixtures())
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred deploying the contract fixtures")
 	}
-	return network, nil
+	return fixturedBackend, nil
 }
 
 func (test SmartContractTest) Run(uncastedNetwork networks.Network) error {
 	// Necessary because Go doesn't have generics
-	network, ok := uncastedNetwork.(*networks_impl.SmartContractAvalancheNetwork)
+	network, ok := uncastedNetwork.(*contract_fixtures.FixturedSmartContractBackend)
 	if !ok {
 		return stacktrace.NewError("Couldn't cast the generic network to the appropriate type")
 	}
-	gethClient, transactor := network.GetFundedCChainClientAndTransactor()
+	defer func() {
+		if err := networks_impl.CloseSmartContractBackend(network); err != nil {
+			logrus.Warnf("An error occurred closing the smart contract backend after the test: %v", err)
+		}
+	}()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), test.runTimeout - test_catalog.TimeoutBuffer)
+	defer cancelFunc()
+
+	return test.RunAgainstBackend(ctx, network)
+}
+
+// Holds the test logic, separate from Run so that it can also run against a SimulatedSmartContractBackend in a plain
+//  'go test'; the context should carry the run deadline
+func (test SmartContractTest) RunAgainstBackend(ctx context.Context, network *contract_fixtures.FixturedSmartContractBackend) error {
+	transactor := network.GetNonceManagingTransactor()
 
 	// TODO vvvvvvvvvvvvvvvvvvvvvvvv REPLACE WITH YOUR CUSTOM TEST CODE vvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvv
-	logrus.Info("Deploying HelloWorld contract...")
-	_, helloWorldDeploymentTxn, _, err := bindings.DeployHelloWorld(transactor, gethClient)
+	// The contracts were deployed during setup, as declared in getContractFixtures
+	helloWorldDeployment, err := network.GetDeployedContract(helloWorldFixtureName)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred deploying the HelloWorld contract on the C-Chain")
-	}
-	if err := waitUntilTransactionMined(gethClient, helloWorldDeploymentTxn.Hash()); err != nil {
-		return stacktrace.Propagate(err, "An error occurred waiting for the HelloWorld contract deployment transaction to be mined")
+		return stacktrace.Propagate(err, "An error occurred getting the HelloWorld contract fixture")
 	}
-	logrus.Info("HelloWorld contract deployed")
+	logrus.Infof("Using HelloWorld contract deployed at '%v'", helloWorldDeployment.Address.Hex())
 
-	logrus.Info("Deploying SimpleStorage contract...")
-	_, storageDeploymentTxn, storageContract, err := bindings.DeploySimpleStorage(transactor, gethClient)
+	storageDeployment, err := network.GetDeployedContract(simpleStorageFixtureName)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred deploying the SimpleStorage contract on the C-Chain")
+		return stacktrace.Propagate(err, "An error occurred getting the SimpleStorage contract fixture")
 	}
-	if err := waitUntilTransactionMined(gethClient, storageDeploymentTxn.Hash()); err != nil {
-		return stacktrace.Propagate(err, "An error occurred waiting for the SimpleStorage contract deployment transaction to be mined")
+	storageContractAddr := storageDeployment.Address
+	storageContract, ok := storageDeployment.Binding.(*bindings.SimpleStorage)
+	if !ok {
+		return stacktrace.NewError("Expected the SimpleStorage fixture's binding to be a *bindings.SimpleStorage, but got '%v'", storageDeployment.Binding)
 	}
-	// NOTE: It's not clear why we need to sleep here - the transaction being mined should be sufficient
-	time.Sleep(5 * time.Second)
-	logrus.Info("SimpleStorage contract deployed")
+	logrus.Infof("Using SimpleStorage contract deployed at '%v'", storageContractAddr.Hex())
 
 	valueToStore := big.NewInt(20)
 	logrus.Infof("Storing value '%v'...", valueToStore)
-	storeValueTxn, err := storageContract.Set(transactor, valueToStore)
+	storeValueTxn, err := transactor.Transact(ctx, simpleStorageContractName, "Set", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return storageContract.Set(opts, valueToStore)
+	})
 	if err != nil {
 		return stacktrace.Propagate(err, "An error occurred storing value '%v' in the contract", valueToStore)
 	}
-	if err := waitUntilTransactionMined(gethClient, storeValueTxn.Hash()); err != nil {
-		return stacktrace.Propagate(err, "An error occurred waiting for the value-storing transaction to be mined")
+	storeValueReceipt, err := waitForTransactionAccepted(ctx, network, storeValueTxn.Hash())
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for the value-storing transaction to be accepted")
 	}
-	// NOTE: It's not clear why we need to sleep here - the transaction being mined should be sufficient
-	time.Sleep(5 * time.Second)
 	logrus.Info("Value stored")
 
+	logrus.Info("Verifying that all nodes agree on the stored value...")
+	stateQuery := networks_impl.CChainStateQuery{
+		MinBlockNumber: storeValueReceipt.BlockNumber.Uint64(),
+		Accounts:       []common.Address{transactor.GetAddress(), storageContractAddr},
+		StorageSlots: map[common.Address][]common.Hash{
+			storageContractAddr: {simpleStorageNumSlot},
+		},
+	}
+	if err := network.AssertCChainStateConsistent(ctx, stateQuery); err != nil {
+		return stacktrace.Propagate(err, "The nodes don't agree on the state after storing the value")
+	}
+	logrus.Info("All nodes agree on the stored value")
+
 	logrus.Info("Retrieving value from contract...")
-	retrievedValue, err := storageContract.Get(&bind.CallOpts{})
+	retrievedValue, err := storageContract.Get(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return stacktrace.Propagate(err, "An error occurred retrieving the value stored in the contract")
 	}
@@ -94,27 +182,65 @@ func (test SmartContractTest) Run(uncastedNetwork networks.Network) error {
 	if valueToStore.Cmp(retrievedValue) != 0 {
 		return stacktrace.NewError("Retrieved value '%v' != stored value '%v'", retrievedValue, valueToStore)
 	}
+
+	// Contracts can also be called by name through the registry, without generated bindings
+	logrus.Info("Retrieving value from contract through the contract registry...")
+	dynamicResults, err := storageDeployment.Contract.Call(&bind.CallOpts{Context: ctx}, "get")
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred retrieving the value stored in the contract through the registry")
+	}
+	dynamicRetrievedValue, ok := dynamicResults[0].(*big.Int)
+	if !ok {
+		return stacktrace.NewError("Expected the registry to return a *big.Int for SimpleStorage.get, but got '%v'", dynamicResults[0])
+	}
+	if valueToStore.Cmp(dynamicRetrievedValue) != 0 {
+		return stacktrace.NewError("Value retrieved through the registry '%v' != stored value '%v'", dynamicRetrievedValue, valueToStore)
+	}
 	// TODO ^^^^^^^^^^^^^^^^^^^^^^^^ REPLACE WITH YOUR CUSTOM TEST CODE ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^
 
+	if err := test.gasReportConfig.FinishReport(gasReportName, network.GetGasUsageRecorder()); err != nil {
+		return stacktrace.Propagate(err, "An error occurred finishing the gas usage report")
+	}
 	return nil
 }
 
+// Both contracts are deployed from the contract registry's artifacts; SimpleStorage also gets a typed handle from the
+//  generated bindings
+func getContractFixtures() []contract_fixtures.ContractFixture {
+	return []contract_fixtures.ContractFixture{
+		{
+			Name:            helloWorldFixtureName,
+			Contract:        helloWorldContractName,
+			ConstructorArgs: nil,
+			Deployer:        "",
+			Bind:            nil,
+		},
+		{
+			Name:            simpleStorageFixtureName,
+			Contract:        simpleStorageContractName,
+			ConstructorArgs: nil,
+			Deployer:        "",
+			Bind: func(address common.Address, backend bind.ContractBackend) (interface{}, error) {
+				return bindings.NewSimpleStorage(address, backend)
+			},
+		},
+	}
+}
 
-// If we try to use a contract immediately after submission without waiting for it to be mined, we'll get a "no contract code at address" error:
+// If we try to use a contract immediately after submission without waiting for it to be accepted, we'll get a "no contract code at address" error:
 // https://github.com/ethereum/go-ethereum/issues/15930#issuecomment-532144875
-func waitUntilTransactionMined(validatorClient *ethclient.Client, transactionHash common.Hash) error {
-	for i := 0; i < maxNumCheckTransactionMinedRetries; i++ {
-		receipt, err := validatorClient.TransactionReceipt(context.Background(), transactionHash)
-		if err == nil && receipt != nil && receipt.BlockNumber != nil {
-			return nil
-		}
-		if i < maxNumCheckTransactionMinedRetries - 1 {
-			time.Sleep(timeBetweenCheckTransactionMinedRetries)
-		}
+// Waiting on every node (rather than just the one we sent the transaction to) means the state is visible no matter which
+//  node we read it back from
+func waitForTransactionAccepted(ctx context.Context, network networks_impl.SmartContractBackend, transactionHash common.Hash) (*types.Receipt, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, transactionAcceptanceTimeout)
+	defer cancelFunc()
+	receipt, err := network.WaitForTransactionAccepted(ctx, transactionHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(
+			err,
+			"Transaction with hash '%v' wasn't successfully accepted by every node within %v",
+			transactionHash.Hex(),
+			transactionAcceptanceTimeout)
 	}
-	return stacktrace.NewError(
-		"Transaction with hash '%v' wasn't mined even after checking %v times with %v between checks",
-		transactionHash.Hex(),
-		maxNumCheckTransactionMinedRetries,
-		timeBetweenCheckTransactionMinedRetries)
+	return receipt, nil
 }
@@ -6,28 +6,53 @@
 package testsuite_impl
 
 import (
-	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/testsuite_impl/smart_contract_test"
+	"github.com/kurtosis-tech/avalanche-smart-contract-sample-testsuite/testsuite/test_catalog"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/sirupsen/logrus"
 )
 
 type SmartContractTestsuite struct {
-	avalancheImage string
+	// Suite-wide dependencies, which each test gets its own copy of
+	testDependencies test_catalog.TestDependencies
+
+	// Decides which of the registered tests are part of the suite
+	testFilter *test_catalog.TestFilter
+
+	timeoutConfig test_catalog.TimeoutConfig
 }
 
-func NewSmartContractTestsuite(avalancheImage string) *SmartContractTestsuite {
-	return &SmartContractTestsuite{avalancheImage: avalancheImage}
+func NewSmartContractTestsuite(
+		testDependencies test_catalog.TestDependencies,
+		testFilter *test_catalog.TestFilter,
+		timeoutConfig test_catalog.TimeoutConfig) *SmartContractTestsuite {
+	return &SmartContractTestsuite{
+		testDependencies: testDependencies,
+		testFilter:       testFilter,
+		timeoutConfig:    timeoutConfig,
+	}
 }
 
+// Builds every test registered in the test catalog that the test filter selects; see registered_tests.go for how test
+//  packages get registered
 func (suite SmartContractTestsuite) GetTests() map[string]testsuite.Test {
-	tests := map[string]testsuite.Test{
-		"smartContractTest": smart_contract_test.NewSmartContractTest(suite.avalancheImage),
+	tests := map[string]testsuite.Test{}
+	skippedTestNames := []string{}
+	for _, registeredTest := range test_catalog.GetRegisteredTests() {
+		if !suite.testFilter.Matches(registeredTest) {
+			skippedTestNames = append(skippedTestNames, registeredTest.Name)
+			continue
+		}
+		tests[registeredTest.Name] = registeredTest.Build(suite.testDependencies, suite.timeoutConfig)
+	}
+	if len(skippedTestNames) > 0 {
+		logrus.Debugf("The test filter skipped tests %v", skippedTestNames)
+	}
+	if len(tests) == 0 {
+		logrus.Warn("The test filter didn't select any of the registered tests, so the suite has no tests")
 	}
-
 	return tests
 }
 
 func (suite SmartContractTestsuite) GetNetworkWidthBits() uint32 {
 	return 8
 }
-
-